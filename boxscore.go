@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+)
+
+// BattingLine a player's batting totals for a game
+type BattingLine struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Position string `json:"position"`
+	// Slot is the batting order slot, Substitute whether the player came off the bench
+	Slot       int  `json:"slot"`
+	Substitute bool `json:"substitute"`
+	AB         int  `json:"ab"`
+	R          int  `json:"r"`
+	H          int  `json:"h"`
+	Doubles    int  `json:"doubles"`
+	Triples    int  `json:"triples"`
+	HR         int  `json:"hr"`
+	RBI        int  `json:"rbi"`
+	BB         int  `json:"bb"`
+	SO         int  `json:"so"`
+}
+
+// PitchingLine a pitcher's totals for a game
+type PitchingLine struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Outs is the number of outs recorded, innings pitched are Outs / 3
+	Outs int `json:"outs"`
+	BF   int `json:"bf"`
+	H    int `json:"h"`
+	R    int `json:"r"`
+	ER   int `json:"er"`
+	BB   int `json:"bb"`
+	SO   int `json:"so"`
+	HR   int `json:"hr"`
+}
+
+// InningsPitched formats outs recorded the way box scores do, e.g. 6.1 for six and a third innings
+func (line PitchingLine) InningsPitched() string {
+	return fmt.Sprintf("%d.%d", line.Outs/3, line.Outs%3)
+}
+
+// BoxScore the batting and pitching lines of both teams along with the linescore
+type BoxScore struct {
+	Batting   [2][]BattingLine  `json:"batting"`
+	Pitching  [2][]PitchingLine `json:"pitching"`
+	Linescore Linescore         `json:"linescore"`
+}
+
+// CreateBoxScore Given a game, total up the batting and pitching lines of every player who appeared
+func CreateBoxScore(game Game) BoxScore {
+	box := BoxScore{Linescore: CreateLinescore(game.Plays)}
+	batters := make(map[string]*BattingLine)
+	pitchers := make(map[string]*PitchingLine)
+
+	for team := 0; team < 2; team++ {
+		scorecard := CreateScorecard(game, team)
+		box.Batting[team] = make([]BattingLine, 0)
+		box.Pitching[team] = make([]PitchingLine, 0)
+
+		for slot, players := range scorecard.Slots {
+			for i, entry := range players.Players {
+				box.Batting[team] = append(box.Batting[team], BattingLine{
+					ID:         entry.Player.ID,
+					Name:       entry.Player.Name,
+					Position:   entry.Player.FieldingPosition.Code,
+					Slot:       slot + 1,
+					Substitute: i > 0,
+				})
+			}
+		}
+		for _, entry := range scorecard.Pitchers {
+			box.Pitching[team] = append(box.Pitching[team], PitchingLine{
+				ID:   entry.Player.ID,
+				Name: entry.Player.Name,
+				ER:   game.EarnedRuns[entry.Player.ID],
+			})
+		}
+	}
+
+	for team := 0; team < 2; team++ {
+		for i := range box.Batting[team] {
+			batters[box.Batting[team][i].ID] = &box.Batting[team][i]
+		}
+		for i := range box.Pitching[team] {
+			pitchers[box.Pitching[team][i].ID] = &box.Pitching[team][i]
+		}
+	}
+
+	// runs are charged to the pitcher who let the runner on base
+	responsible := make(map[string]string)
+
+	for _, play := range game.Plays {
+		for _, sub := range play.Substitutions {
+			if pitcher, ok := responsible[sub.ReplacedID]; ok && sub.Player.FieldingPosition.ID == "12" {
+				responsible[sub.Player.ID] = pitcher
+			}
+		}
+
+		batter, ok := batters[play.BatterID]
+		if !ok {
+			batter = &BattingLine{}
+		}
+		pitcher, ok := pitchers[play.PitcherID]
+		if !ok {
+			pitcher = &PitchingLine{}
+		}
+		event := play.Event
+
+		if event.IsPlateAppearance() {
+			responsible[play.BatterID] = play.PitcherID
+			pitcher.BF++
+
+			if event.IsAtBat() {
+				batter.AB++
+			}
+			switch event.Type {
+			case EventSingle, EventDouble, EventTriple, EventHomeRun:
+				batter.H++
+				pitcher.H++
+			case EventWalk, EventIntentionalWalk:
+				batter.BB++
+				pitcher.BB++
+			case EventStrikeout:
+				batter.SO++
+				pitcher.SO++
+			}
+			switch event.Type {
+			case EventDouble:
+				batter.Doubles++
+			case EventTriple:
+				batter.Triples++
+			case EventHomeRun:
+				batter.HR++
+				pitcher.HR++
+			}
+			batter.RBI += play.RBI
+		}
+
+		if play.After.Outs > play.Before.Outs {
+			pitcher.Outs += play.After.Outs - play.Before.Outs
+		}
+
+		for _, scorer := range play.Scorers {
+			if line, ok := batters[scorer]; ok {
+				line.R++
+			}
+			if line, ok := pitchers[responsible[scorer]]; ok {
+				line.R++
+			}
+		}
+	}
+
+	return box
+}
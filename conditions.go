@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WindDirection which way the wind blew during a game, one of the WindDirections codes or blank when it wasn't recorded
+type WindDirection string
+
+// FieldCondition how wet the field was, one of the FieldConditions codes or blank when it wasn't recorded
+type FieldCondition string
+
+// Precipitation what was falling during a game, one of the Precipitations codes or blank when it wasn't recorded
+type Precipitation string
+
+// Sky what the sky looked like during a game, one of the Skies codes or blank when it wasn't recorded
+type Sky string
+
+// parseCondition keeps an event file code when it is one of the known values, anything else (unknown included) isn't recorded
+func parseCondition(code string, known func(code string) bool) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if !known(code) {
+		return ""
+	}
+
+	return code
+}
+
+// marshalCondition writes a code to json, null when it wasn't recorded
+func marshalCondition(code string) ([]byte, error) {
+	if code == "" {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(code)
+}
+
+// unmarshalCondition reads a code back from json, checking it is one of the known values
+func unmarshalCondition(data []byte, known func(code string) bool) (string, error) {
+	var code *string
+	if err := json.Unmarshal(data, &code); err != nil {
+		return "", err
+	}
+	if code == nil {
+		return "", nil
+	}
+	if !known(*code) {
+		return "", fmt.Errorf("unknown condition '%s'", *code)
+	}
+
+	return *code, nil
+}
+
+func knownWindDirection(code string) bool {
+	_, ok := WindDirections[WindDirection(code)]
+	return ok
+}
+
+func knownFieldCondition(code string) bool {
+	_, ok := FieldConditions[FieldCondition(code)]
+	return ok
+}
+
+func knownPrecipitation(code string) bool {
+	_, ok := Precipitations[Precipitation(code)]
+	return ok
+}
+
+func knownSky(code string) bool {
+	_, ok := Skies[Sky(code)]
+	return ok
+}
+
+// ParseWindDirection Given a winddir code, return the direction, blank for unknown
+func ParseWindDirection(code string) WindDirection {
+	return WindDirection(parseCondition(code, knownWindDirection))
+}
+
+// ParseFieldCondition Given a fieldcond code, return the condition, blank for unknown
+func ParseFieldCondition(code string) FieldCondition {
+	return FieldCondition(parseCondition(code, knownFieldCondition))
+}
+
+// ParsePrecipitation Given a precip code, return the precipitation, blank for unknown
+func ParsePrecipitation(code string) Precipitation {
+	return Precipitation(parseCondition(code, knownPrecipitation))
+}
+
+// ParseSky Given a sky code, return the sky, blank for unknown
+func ParseSky(code string) Sky {
+	return Sky(parseCondition(code, knownSky))
+}
+
+func (direction WindDirection) String() string  { return WindDirections[direction] }
+func (condition FieldCondition) String() string { return FieldConditions[condition] }
+func (precip Precipitation) String() string     { return Precipitations[precip] }
+func (sky Sky) String() string                  { return Skies[sky] }
+
+func (direction WindDirection) MarshalJSON() ([]byte, error) {
+	return marshalCondition(string(direction))
+}
+func (condition FieldCondition) MarshalJSON() ([]byte, error) {
+	return marshalCondition(string(condition))
+}
+func (precip Precipitation) MarshalJSON() ([]byte, error) { return marshalCondition(string(precip)) }
+func (sky Sky) MarshalJSON() ([]byte, error)              { return marshalCondition(string(sky)) }
+
+func (direction *WindDirection) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCondition(data, knownWindDirection)
+	*direction = WindDirection(code)
+	return err
+}
+
+func (condition *FieldCondition) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCondition(data, knownFieldCondition)
+	*condition = FieldCondition(code)
+	return err
+}
+
+func (precip *Precipitation) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCondition(data, knownPrecipitation)
+	*precip = Precipitation(code)
+	return err
+}
+
+func (sky *Sky) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCondition(data, knownSky)
+	*sky = Sky(code)
+	return err
+}
+
+// recorded treats a blank or unknown value from an event file as not recorded, giving nil
+func recorded(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "unknown") {
+		return nil
+	}
+
+	return &value
+}
+
+// recordedNumber reads a number from an event file, giving nil when it is missing or is the marker the file uses for unknown
+func recordedNumber(value string, unknown int) *int {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n == unknown {
+		return nil
+	}
+
+	return &n
+}
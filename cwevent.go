@@ -0,0 +1,593 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CWEventDefaultFields the fields cwevent writes when it isn't given a field list
+const CWEventDefaultFields = "0-6,8-9,12-13,16-17,26-40,43-45,51,58-61"
+
+// cweventRow everything known about a play while writing its cwevent fields
+type cweventRow struct {
+	game   *Game
+	play   Play
+	number int
+	last   bool
+	// defense holds the fielders of the team in the field by position
+	defense [11]string
+	// slot and position are the batter's batting order slot and current fielding position
+	slot     int
+	position int
+	leadoff  bool
+	// responsible holds the pitchers charged with the runners on first, second and third
+	responsible [3]string
+	// pinchRunners holds the players replaced by pinch runners on each base before this play
+	pinchRunners [3]string
+	// pinchHit is the player replaced by a pinch hitter before this play, and the position they played
+	pinchHit         string
+	pinchHitPosition int
+	destinations     [4]int
+	fielding         fieldingCredits
+}
+
+// cweventField a numbered cwevent field, its header name and how its value is written
+type cweventField struct {
+	header string
+	value  func(row *cweventRow) string
+}
+
+// cweventText a quoted text value
+func cweventText(text string) string {
+	return `"` + strings.Replace(text, `"`, `""`, -1) + `"`
+}
+
+// cweventFlag a quoted T or F
+func cweventFlag(flag bool) string {
+	if flag {
+		return `"T"`
+	}
+
+	return `"F"`
+}
+
+// cweventNumber an unquoted number
+func cweventNumber(n int) string {
+	return strconv.Itoa(n)
+}
+
+// cweventCount the balls (0) or strikes (1) in the count of a play, 0 when the count wasn't recorded
+func cweventCount(count string, index int) string {
+	if len(count) != 2 {
+		return "0"
+	}
+	n, err := strconv.Atoi(count[index : index+1])
+	if err != nil {
+		return "0"
+	}
+
+	return cweventNumber(n)
+}
+
+// cweventHand the side a player bats (bats true) or throws from, facing the given pitcher. Switch hitters bat from the side opposite the pitcher's arm.
+func cweventHand(player Player, bats bool, pitcher Player) string {
+	hand := player.Throws
+	if bats {
+		hand = player.Bats
+		if hand == "B" {
+			hand = "L"
+			if pitcher.Throws == "L" {
+				hand = "R"
+			}
+		}
+	}
+	if hand == "" {
+		return `"?"`
+	}
+
+	return cweventText(hand)
+}
+
+// battedBallTypes the modifiers that give the trajectory of a batted ball
+var battedBallTypes = regexp.MustCompile("^B?([GLFP])(?:DP|TP)?(\\d[0-9A-Z]*)?$")
+
+// hitLocation matches a modifier giving where a ball was hit, either alone or after its trajectory
+var hitLocation = regexp.MustCompile("^(?:B?[GLFP])?(\\d{1,2}[A-Z]*)$")
+
+// battedBall the trajectory (G, L, F or P), bunt flag and location of the ball hit on a play
+func battedBall(event Event) (string, bool, string) {
+	trajectory := ""
+	bunt := false
+	location := ""
+
+	for _, modifier := range event.Modifiers {
+		if matches := battedBallTypes.FindStringSubmatch(modifier); matches != nil && trajectory == "" {
+			trajectory = matches[1]
+			bunt = strings.HasPrefix(modifier, "B")
+		}
+		if matches := hitLocation.FindStringSubmatch(modifier); matches != nil && location == "" {
+			location = matches[1]
+		}
+	}
+
+	return trajectory, bunt, location
+}
+
+// fieldedBy the fielder who first handled the batted ball, 0 when none is recorded
+func fieldedBy(event Event) int {
+	if !event.IsPlateAppearance() || event.Type == EventStrikeout {
+		return 0
+	}
+
+	for _, r := range strings.SplitN(event.BasicPlay, "+", 2)[0] {
+		if r >= '1' && r <= '9' {
+			return int(r - '0')
+		}
+	}
+
+	return 0
+}
+
+// fieldingCredits the putouts and assists on a play along with the fielders who made the play on the batter and each runner
+type fieldingCredits struct {
+	putouts []int
+	assists []int
+	// plays holds the fielders making the play on the batter (0) and runners (1-3), e.g. 64
+	plays [4]string
+}
+
+// credit records a sequence of fielders recording an out, the last one getting the putout and the others assists
+func (credits *fieldingCredits) credit(from int, fielders string) {
+	if fielders == "" || strings.Contains(fielders, "E") {
+		return
+	}
+
+	credits.plays[from] = fielders
+	for i, r := range fielders {
+		position := int(r - '0')
+		if i == len(fielders)-1 {
+			credits.putouts = append(credits.putouts, position)
+		} else if !containsInt(credits.assists, position) {
+			credits.assists = append(credits.assists, position)
+		}
+	}
+}
+
+// containsInt whether a number is in the list
+func containsInt(list []int, value int) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runnerOutFielders pulls the fielders out of the notes on a runner out, such as (25) or (E5)
+var runnerOutFielders = regexp.MustCompile("\\(([1-9]+)\\)")
+
+// creditFielding works out who made each out on a play
+func creditFielding(event Event) fieldingCredits {
+	credits := fieldingCredits{}
+	parts := strings.SplitN(event.BasicPlay, "+", 2)
+	primary := parts[0]
+
+	switch {
+	case strikeoutMatcher.MatchString(primary) && batterOut(event):
+		fielders := strikeoutMatcher.FindStringSubmatch(primary)[1]
+		if fielders == "" {
+			fielders = "2"
+		}
+		credits.credit(0, fielders)
+	case event.Type == EventGenericOut:
+		segments := fieldingOutSegments.FindAllStringSubmatch(primary, -1)
+		for i, segment := range segments {
+			switch runner := segment[2]; {
+			case runner == "B" || (runner == "" && i == len(segments)-1):
+				credits.credit(0, segment[1])
+			case runner != "":
+				credits.credit(BaseNumber(runner), segment[1])
+			}
+		}
+	}
+
+	runnerPlays := []string{}
+	if event.Type != EventGenericOut && !strikeoutMatcher.MatchString(primary) {
+		runnerPlays = append(runnerPlays, primary)
+	}
+	if len(parts) == 2 {
+		runnerPlays = append(runnerPlays, strings.Split(parts[1], ";")...)
+	}
+	for _, play := range runnerPlays {
+		if matches := caughtStealing.FindStringSubmatch(play); matches != nil {
+			credits.credit(BaseNumber(matches[2])-1, matches[3])
+		} else if matches := pickoffMatcher.FindStringSubmatch(play); matches != nil {
+			credits.credit(BaseNumber(matches[1]), matches[2])
+		}
+	}
+
+	if advances := strings.SplitN(event.Source, ".", 2); len(advances) == 2 {
+		for _, v := range strings.Split(advances[1], ";") {
+			matches := advanceMatcher.FindStringSubmatch(v)
+			if matches == nil || matches[2] != "X" || errorCredits.MatchString(matches[4]) {
+				continue
+			}
+			if fielders := runnerOutFielders.FindStringSubmatch(matches[4]); fielders != nil {
+				credits.credit(BaseNumber(matches[1]), fielders[1])
+			}
+		}
+	}
+
+	return credits
+}
+
+// batterOut whether the batter was put out on the play
+func batterOut(event Event) bool {
+	for _, advance := range event.Advances {
+		if advance.From == 0 {
+			return advance.Out
+		}
+	}
+
+	return false
+}
+
+// runnerPlay whether the runner on a base (1-3) is the subject of a stolen base (SB), caught stealing (CS) or pickoff (PO) on the play
+func runnerPlay(event Event, base int, kind string) bool {
+	plays := strings.Split(strings.Replace(strings.SplitN(event.BasicPlay, ".", 2)[0], "+", ";", -1), ";")
+
+	for _, play := range plays {
+		switch kind {
+		case "SB":
+			if matches := stolenBaseMatcher.FindStringSubmatch(play); matches != nil && BaseNumber(matches[1]) == base+1 {
+				return true
+			}
+		case "CS":
+			if matches := caughtStealing.FindStringSubmatch(play); matches != nil && BaseNumber(matches[2]) == base+1 {
+				return true
+			}
+		case "PO":
+			if matches := caughtStealing.FindStringSubmatch(play); matches != nil && matches[1] == "PO" && BaseNumber(matches[2]) == base+1 {
+				return true
+			}
+			if matches := pickoffMatcher.FindStringSubmatch(play); matches != nil && BaseNumber(matches[1]) == base {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// errorType whether the nth error on a play was on a throw (T) or fielding the ball (F), N when there wasn't one
+func errorType(event Event, n int) string {
+	if n >= len(event.Errors) {
+		return `"N"`
+	}
+
+	// a throwing error is marked TH in the notes it was written in, such as (E2/TH2), or as a modifier of the play when
+	// it's the batter's
+	at := errorCredits.FindAllStringIndex(event.Source, -1)[n][0]
+	if open := strings.LastIndex(event.Source[:at], "("); open > strings.LastIndex(event.Source[:at], ")") {
+		notes := event.Source[open:]
+		if end := strings.Index(notes, ")"); end >= 0 {
+			notes = notes[:end]
+		}
+		if strings.Contains(notes, "/TH") {
+			return `"T"`
+		}
+		return `"F"`
+	}
+	for _, modifier := range event.Modifiers {
+		if strings.HasPrefix(modifier, "TH") {
+			return `"T"`
+		}
+	}
+
+	return `"F"`
+}
+
+// destinations where the batter (0) and the runners on first, second and third ended up: 0 out or not moved for the batter,
+// 1-3 for a base, 4 for a run and 5 for an unearned run
+func destinations(play Play) [4]int {
+	dest := [4]int{}
+	unearned := make(map[int]bool)
+	for _, advance := range play.Event.Advances {
+		if advance.To == Home && advance.Unearned {
+			unearned[advance.From] = true
+		}
+	}
+
+	players := [4]string{play.BatterID, play.Before.Bases[0], play.Before.Bases[1], play.Before.Bases[2]}
+	for from, id := range players {
+		if id == "" {
+			continue
+		}
+		for base, runner := range play.After.Bases {
+			if runner == id {
+				dest[from] = base + 1
+			}
+		}
+		if Contains(play.Scorers, id) {
+			dest[from] = Home
+			if unearned[from] {
+				dest[from] = Home + 1
+			}
+		}
+	}
+
+	return dest
+}
+
+// cweventFields the 97 standard cwevent fields, in cwevent's numbering
+var cweventFields = []cweventField{
+	{"GAME_ID", func(r *cweventRow) string { return cweventText(r.game.ID) }},
+	{"AWAY_TEAM_ID", func(r *cweventRow) string { return cweventText(r.game.Info.Visteam.ID) }},
+	{"INN_CT", func(r *cweventRow) string { return cweventNumber(r.play.Inning) }},
+	{"BAT_HOME_ID", func(r *cweventRow) string { return cweventNumber(r.play.Team) }},
+	{"OUTS_CT", func(r *cweventRow) string { return cweventNumber(r.play.Before.Outs) }},
+	{"BALLS_CT", func(r *cweventRow) string { return cweventCount(r.play.Count, 0) }},
+	{"STRIKES_CT", func(r *cweventRow) string { return cweventCount(r.play.Count, 1) }},
+	{"PITCH_SEQ_TX", func(r *cweventRow) string { return cweventText(r.play.Pitches) }},
+	{"AWAY_SCORE_CT", func(r *cweventRow) string { return cweventNumber(r.play.Before.Score[0]) }},
+	{"HOME_SCORE_CT", func(r *cweventRow) string { return cweventNumber(r.play.Before.Score[1]) }},
+	{"BAT_ID", func(r *cweventRow) string { return cweventText(r.play.BatterID) }},
+	{"BAT_HAND_CD", func(r *cweventRow) string { return r.batterHand() }},
+	{"RESP_BAT_ID", func(r *cweventRow) string { return cweventText(r.play.BatterID) }},
+	{"RESP_BAT_HAND_CD", func(r *cweventRow) string { return r.batterHand() }},
+	{"PIT_ID", func(r *cweventRow) string { return cweventText(r.play.PitcherID) }},
+	{"PIT_HAND_CD", func(r *cweventRow) string { return cweventHand(r.player(r.play.PitcherID), false, Player{}) }},
+	{"RESP_PIT_ID", func(r *cweventRow) string { return cweventText(r.play.PitcherID) }},
+	{"RESP_PIT_HAND_CD", func(r *cweventRow) string { return cweventHand(r.player(r.play.PitcherID), false, Player{}) }},
+	{"POS2_FLD_ID", func(r *cweventRow) string { return cweventText(r.defense[2]) }},
+	{"POS3_FLD_ID", func(r *cweventRow) string { return cweventText(r.defense[3]) }},
+	{"POS4_FLD_ID", func(r *cweventRow) string { return cweventText(r.defense[4]) }},
+	{"POS5_FLD_ID", func(r *cweventRow) string { return cweventText(r.defense[5]) }},
+	{"POS6_FLD_ID", func(r *cweventRow) string { return cweventText(r.defense[6]) }},
+	{"POS7_FLD_ID", func(r *cweventRow) string { return cweventText(r.defense[7]) }},
+	{"POS8_FLD_ID", func(r *cweventRow) string { return cweventText(r.defense[8]) }},
+	{"POS9_FLD_ID", func(r *cweventRow) string { return cweventText(r.defense[9]) }},
+	{"BASE1_RUN_ID", func(r *cweventRow) string { return cweventText(r.play.Before.Bases[0]) }},
+	{"BASE2_RUN_ID", func(r *cweventRow) string { return cweventText(r.play.Before.Bases[1]) }},
+	{"BASE3_RUN_ID", func(r *cweventRow) string { return cweventText(r.play.Before.Bases[2]) }},
+	{"EVENT_TX", func(r *cweventRow) string { return cweventText(r.play.Event.Source) }},
+	{"LEADOFF_FL", func(r *cweventRow) string { return cweventFlag(r.leadoff) }},
+	{"PH_FL", func(r *cweventRow) string { return cweventFlag(r.position == 11) }},
+	{"BAT_FLD_CD", func(r *cweventRow) string { return cweventNumber(r.position) }},
+	{"BAT_LINEUP_ID", func(r *cweventRow) string { return cweventNumber(r.slot) }},
+	{"EVENT_CD", func(r *cweventRow) string { return cweventNumber(r.play.Event.Type) }},
+	{"BAT_EVENT_FL", func(r *cweventRow) string { return cweventFlag(r.play.Event.IsPlateAppearance()) }},
+	{"AB_FL", func(r *cweventRow) string { return cweventFlag(r.play.Event.IsAtBat()) }},
+	{"H_CD", func(r *cweventRow) string { return cweventNumber(r.play.Event.HitValue()) }},
+	{"SH_FL", func(r *cweventRow) string { return cweventFlag(r.play.Event.Sacrifice) }},
+	{"SF_FL", func(r *cweventRow) string { return cweventFlag(r.play.Event.SacFly) }},
+	{"EVENT_OUTS_CT", func(r *cweventRow) string { return cweventNumber(r.play.After.Outs - r.play.Before.Outs) }},
+	{"DP_FL", func(r *cweventRow) string { return cweventFlag(r.play.Event.DoublePlay) }},
+	{"TP_FL", func(r *cweventRow) string { return cweventFlag(r.play.Event.TriplePlay) }},
+	{"RBI_CT", func(r *cweventRow) string { return cweventNumber(r.play.RBI) }},
+	{"WP_FL", func(r *cweventRow) string {
+		return cweventFlag(r.play.Event.Type == EventWildPitch || strings.Contains(r.play.Event.BasicPlay, "+WP"))
+	}},
+	{"PB_FL", func(r *cweventRow) string {
+		return cweventFlag(r.play.Event.Type == EventPassedBall || strings.Contains(r.play.Event.BasicPlay, "+PB"))
+	}},
+	{"FLD_CD", func(r *cweventRow) string { return cweventNumber(fieldedBy(r.play.Event)) }},
+	{"BATTEDBALL_CD", func(r *cweventRow) string {
+		trajectory, _, _ := battedBall(r.play.Event)
+		return cweventText(trajectory)
+	}},
+	{"BUNT_FL", func(r *cweventRow) string { _, bunt, _ := battedBall(r.play.Event); return cweventFlag(bunt) }},
+	{"FOUL_FL", func(r *cweventRow) string { return cweventFlag(Contains(r.play.Event.Modifiers, "FL")) }},
+	{"BATTEDBALL_LOC_TX", func(r *cweventRow) string { _, _, location := battedBall(r.play.Event); return cweventText(location) }},
+	{"ERR_CT", func(r *cweventRow) string { return cweventNumber(len(r.play.Event.Errors)) }},
+	{"ERR1_FLD_CD", func(r *cweventRow) string { return r.errorFielder(0) }},
+	{"ERR1_CD", func(r *cweventRow) string { return errorType(r.play.Event, 0) }},
+	{"ERR2_FLD_CD", func(r *cweventRow) string { return r.errorFielder(1) }},
+	{"ERR2_CD", func(r *cweventRow) string { return errorType(r.play.Event, 1) }},
+	{"ERR3_FLD_CD", func(r *cweventRow) string { return r.errorFielder(2) }},
+	{"ERR3_CD", func(r *cweventRow) string { return errorType(r.play.Event, 2) }},
+	{"BAT_DEST_ID", func(r *cweventRow) string { return cweventNumber(r.destinations[0]) }},
+	{"RUN1_DEST_ID", func(r *cweventRow) string { return cweventNumber(r.destinations[1]) }},
+	{"RUN2_DEST_ID", func(r *cweventRow) string { return cweventNumber(r.destinations[2]) }},
+	{"RUN3_DEST_ID", func(r *cweventRow) string { return cweventNumber(r.destinations[3]) }},
+	{"BAT_PLAY_TX", func(r *cweventRow) string { return cweventText(r.fielding.plays[0]) }},
+	{"RUN1_PLAY_TX", func(r *cweventRow) string { return cweventText(r.fielding.plays[1]) }},
+	{"RUN2_PLAY_TX", func(r *cweventRow) string { return cweventText(r.fielding.plays[2]) }},
+	{"RUN3_PLAY_TX", func(r *cweventRow) string { return cweventText(r.fielding.plays[3]) }},
+	{"RUN1_SB_FL", func(r *cweventRow) string { return cweventFlag(runnerPlay(r.play.Event, 1, "SB")) }},
+	{"RUN2_SB_FL", func(r *cweventRow) string { return cweventFlag(runnerPlay(r.play.Event, 2, "SB")) }},
+	{"RUN3_SB_FL", func(r *cweventRow) string { return cweventFlag(runnerPlay(r.play.Event, 3, "SB")) }},
+	{"RUN1_CS_FL", func(r *cweventRow) string { return cweventFlag(runnerPlay(r.play.Event, 1, "CS")) }},
+	{"RUN2_CS_FL", func(r *cweventRow) string { return cweventFlag(runnerPlay(r.play.Event, 2, "CS")) }},
+	{"RUN3_CS_FL", func(r *cweventRow) string { return cweventFlag(runnerPlay(r.play.Event, 3, "CS")) }},
+	{"RUN1_PK_FL", func(r *cweventRow) string { return cweventFlag(runnerPlay(r.play.Event, 1, "PO")) }},
+	{"RUN2_PK_FL", func(r *cweventRow) string { return cweventFlag(runnerPlay(r.play.Event, 2, "PO")) }},
+	{"RUN3_PK_FL", func(r *cweventRow) string { return cweventFlag(runnerPlay(r.play.Event, 3, "PO")) }},
+	{"RUN1_RESP_PIT_ID", func(r *cweventRow) string { return cweventText(r.responsible[0]) }},
+	{"RUN2_RESP_PIT_ID", func(r *cweventRow) string { return cweventText(r.responsible[1]) }},
+	{"RUN3_RESP_PIT_ID", func(r *cweventRow) string { return cweventText(r.responsible[2]) }},
+	{"GAME_NEW_FL", func(r *cweventRow) string { return cweventFlag(r.number == 1) }},
+	{"GAME_END_FL", func(r *cweventRow) string { return cweventFlag(r.last) }},
+	{"PR_RUN1_FL", func(r *cweventRow) string { return cweventFlag(r.pinchRunners[0] != "") }},
+	{"PR_RUN2_FL", func(r *cweventRow) string { return cweventFlag(r.pinchRunners[1] != "") }},
+	{"PR_RUN3_FL", func(r *cweventRow) string { return cweventFlag(r.pinchRunners[2] != "") }},
+	{"REMOVED_FOR_PR_RUN1_ID", func(r *cweventRow) string { return cweventText(r.pinchRunners[0]) }},
+	{"REMOVED_FOR_PR_RUN2_ID", func(r *cweventRow) string { return cweventText(r.pinchRunners[1]) }},
+	{"REMOVED_FOR_PR_RUN3_ID", func(r *cweventRow) string { return cweventText(r.pinchRunners[2]) }},
+	{"REMOVED_FOR_PH_BAT_ID", func(r *cweventRow) string { return cweventText(r.pinchHit) }},
+	{"REMOVED_FOR_PH_BAT_FLD_CD", func(r *cweventRow) string { return cweventNumber(r.pinchHitPosition) }},
+	{"PO1_FLD_CD", func(r *cweventRow) string { return r.fielder(r.fielding.putouts, 0) }},
+	{"PO2_FLD_CD", func(r *cweventRow) string { return r.fielder(r.fielding.putouts, 1) }},
+	{"PO3_FLD_CD", func(r *cweventRow) string { return r.fielder(r.fielding.putouts, 2) }},
+	{"ASS1_FLD_CD", func(r *cweventRow) string { return r.fielder(r.fielding.assists, 0) }},
+	{"ASS2_FLD_CD", func(r *cweventRow) string { return r.fielder(r.fielding.assists, 1) }},
+	{"ASS3_FLD_CD", func(r *cweventRow) string { return r.fielder(r.fielding.assists, 2) }},
+	{"ASS4_FLD_CD", func(r *cweventRow) string { return r.fielder(r.fielding.assists, 3) }},
+	{"ASS5_FLD_CD", func(r *cweventRow) string { return r.fielder(r.fielding.assists, 4) }},
+	{"EVENT_ID", func(r *cweventRow) string { return cweventNumber(r.number) }},
+}
+
+// player finds someone who appeared in the game, from the lineups or substitutions
+func (r *cweventRow) player(id string) Player {
+	for _, player := range append(append([]Player{}, r.game.Lineup.Visitor...), r.game.Lineup.Home...) {
+		if player.ID == id {
+			return player
+		}
+	}
+	for _, play := range r.game.Plays {
+		for _, sub := range play.Substitutions {
+			if sub.Player.ID == id {
+				return sub.Player
+			}
+		}
+	}
+
+	return Player{ID: id}
+}
+
+// batterHand the side the batter hit from against the pitcher
+func (r *cweventRow) batterHand() string {
+	return cweventHand(r.player(r.play.BatterID), true, r.player(r.play.PitcherID))
+}
+
+// errorFielder the fielder charged with the nth error on the play, 0 when there wasn't one
+func (r *cweventRow) errorFielder(n int) string {
+	if n >= len(r.play.Event.Errors) {
+		return "0"
+	}
+
+	return cweventNumber(r.play.Event.Errors[n])
+}
+
+// fielder the nth fielder in a list of putouts or assists, 0 when there are fewer
+func (r *cweventRow) fielder(list []int, n int) string {
+	if n >= len(list) {
+		return "0"
+	}
+
+	return cweventNumber(list[n])
+}
+
+// ParseFieldList Given a field list in the -f format of the Chadwick tools, such as 0-6,8,10-12, and how many fields there are, return the field numbers
+func ParseFieldList(spec string, count int) ([]int, error) {
+	fields := make([]int, 0)
+
+	for _, part := range strings.Split(spec, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("bad field list '%s'", spec)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("bad field list '%s'", spec)
+			}
+		}
+		if first < 0 || last >= count || first > last {
+			return nil, fmt.Errorf("fields must be between 0 and %d", count-1)
+		}
+
+		for field := first; field <= last; field++ {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
+
+// cweventRows replays a game, working out the state cwevent reports for each play
+func cweventRows(game *Game) []cweventRow {
+	rows := make([]cweventRow, 0)
+	state := NewGameState(game.Lineup)
+	positions := make(map[string]int)
+	slots := make(map[string]int)
+	responsible := make(map[string]string)
+	batted := make(map[[2]int]bool)
+
+	for _, player := range append(append([]Player{}, game.Lineup.Visitor...), game.Lineup.Home...) {
+		positions[player.ID], _ = strconv.Atoi(player.FieldingPosition.ID)
+		slots[player.ID] = player.BattingPosition
+	}
+
+	for i, play := range game.Plays {
+		row := cweventRow{game: game, play: play, number: i + 1, last: i == len(game.Plays)-1}
+
+		for _, sub := range play.Substitutions {
+			position, _ := strconv.Atoi(sub.Player.FieldingPosition.ID)
+			switch {
+			case position == 12:
+				for base, runner := range play.Before.Bases {
+					if runner == sub.Player.ID {
+						row.pinchRunners[base] = sub.ReplacedID
+					}
+				}
+				responsible[sub.Player.ID] = responsible[sub.ReplacedID]
+			case position == 11 && sub.Player.ID == play.BatterID:
+				row.pinchHit = sub.ReplacedID
+				row.pinchHitPosition = positions[sub.ReplacedID]
+			}
+
+			state.Substitute(sub)
+			positions[sub.Player.ID] = position
+			slots[sub.Player.ID] = sub.Player.BattingPosition
+		}
+
+		row.defense = state.Defense[1-play.Team]
+		row.slot = slots[play.BatterID]
+		row.position = positions[play.BatterID]
+		half := [2]int{play.Inning, play.Team}
+		row.leadoff = !batted[half]
+		for base, runner := range play.Before.Bases {
+			if runner != "" {
+				row.responsible[base] = responsible[runner]
+			}
+		}
+		row.destinations = destinations(play)
+		row.fielding = creditFielding(play.Event)
+
+		if play.Event.IsPlateAppearance() {
+			batted[half] = true
+			responsible[play.BatterID] = play.PitcherID
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// CreateEventCSV Given games and the cwevent field numbers to write, return the plays of the games in cwevent's csv format,
+// with a header line of field names first when header is set
+func CreateEventCSV(games []Game, fields []int, header bool) string {
+	b := strings.Builder{}
+
+	if header {
+		names := make([]string, 0)
+		for _, field := range fields {
+			names = append(names, cweventText(cweventFields[field].header))
+		}
+		b.WriteString(strings.Join(names, ",") + "\n")
+	}
+
+	for i := range games {
+		for _, row := range cweventRows(&games[i]) {
+			values := make([]string, 0)
+			for _, field := range fields {
+				values = append(values, cweventFields[field].value(&row))
+			}
+			b.WriteString(strings.Join(values, ",") + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// writeEventCSV writes the plays of the games in cwevent's csv format to the given file
+func writeEventCSV(outputPath string, games []Game, fields []int, header bool) error {
+	return ioutil.WriteFile(outputPath, []byte(CreateEventCSV(games, fields, header)), 0644)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// gameWithPlays the first game's header and starting lineups in the test event file, followed by the given records in
+// place of its own plays
+func gameWithPlays(t *testing.T, plays ...string) string {
+	t.Helper()
+	data, err := ioutil.ReadFile(eventPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := make([]string, 0)
+	for i, record := range strings.Split(string(data), "\r\n") {
+		if i > 0 && strings.HasPrefix(record, "id,") {
+			break
+		}
+		if !strings.HasPrefix(record, "play,") && !strings.HasPrefix(record, "sub,") && !strings.HasPrefix(record, "com,") && !strings.HasPrefix(record, "data,") {
+			records = append(records, record)
+		}
+	}
+
+	return strings.Join(append(records, plays...), "\r\n") + "\r\n"
+}
+
+func TestCreateEventCSVFieldNumbers(t *testing.T) {
+	tests := []struct {
+		field  int
+		header string
+	}{
+		{0, "GAME_ID"},
+		{7, "PITCH_SEQ_TX"},
+		{18, "POS2_FLD_ID"},
+		{25, "POS9_FLD_ID"},
+		{29, "EVENT_TX"},
+		{34, "EVENT_CD"},
+		{37, "H_CD"},
+		{40, "EVENT_OUTS_CT"},
+		{46, "FLD_CD"},
+		{51, "ERR_CT"},
+		{58, "BAT_DEST_ID"},
+		{62, "BAT_PLAY_TX"},
+		{66, "RUN1_SB_FL"},
+		{75, "RUN1_RESP_PIT_ID"},
+		{78, "GAME_NEW_FL"},
+		{86, "REMOVED_FOR_PH_BAT_ID"},
+		{88, "PO1_FLD_CD"},
+		{91, "ASS1_FLD_CD"},
+		{95, "ASS5_FLD_CD"},
+		{96, "EVENT_ID"},
+	}
+
+	if len(cweventFields) != 97 {
+		t.Errorf("want 97 standard fields, got %d", len(cweventFields))
+	}
+	for _, test := range tests {
+		header := strings.Split(CreateEventCSV(nil, []int{test.field}, true), "\n")[0]
+		if header != `"`+test.header+`"` {
+			t.Errorf("field %d: want %s, got %s", test.field, test.header, header)
+		}
+	}
+}
+
+func TestCreateEventCSVValues(t *testing.T) {
+	games := CreateGames([]byte(gameWithPlays(t,
+		"play,1,0,colev001,10,BX,S7",
+		"play,1,0,mcgew001,11,BC,SB2",
+		"play,1,0,mcgew001,32,BC.BBFFS,K",
+		"play,1,0,herrt001,00,X,63/G6",
+		"play,1,0,clarj001,01,CX,8/F8.2-H",
+	)))
+
+	// outs, count and pitches, the event, its hit value, outs and rbi, the fielder and batted ball, where the batter and
+	// runners on first and second ended up, a stolen base, the first putout and assist, and the event number
+	fields := []int{4, 5, 6, 7, 29, 34, 35, 37, 40, 43, 46, 47, 50, 58, 59, 60, 66, 88, 91, 96}
+	want := []string{
+		`0,1,0,"BX","S7",20,"T",1,0,0,7,"","",1,0,0,"F",0,0,1`,
+		`0,1,1,"BC","SB2",4,"F",0,0,0,0,"","",0,2,0,"T",0,0,2`,
+		`0,3,2,"BC.BBFFS","K",3,"T",0,1,0,0,"","",0,0,2,"F",2,0,3`,
+		`1,0,0,"X","63/G6",2,"T",0,1,0,6,"G","6",0,0,2,"F",3,6,4`,
+		`2,0,1,"CX","8/F8.2-H",2,"T",0,1,1,8,"F","8",0,0,4,"F",8,0,5`,
+	}
+
+	got := strings.Split(strings.TrimSuffix(CreateEventCSV(games, fields, false), "\n"), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("want %d rows, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d: want %s, got %s", i, want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// CWGameDefaultFields the fields cwgame writes when it isn't given a field list
+const CWGameDefaultFields = "0-83"
+
+// cwgameRow everything known about a game while writing its cwgame fields
+type cwgameRow struct {
+	game      *Game
+	linescore Linescore
+	// teamGames is how many games the visitor and home team had played in the file, this one included
+	teamGames [2]int
+}
+
+// cwgameField a numbered cwgame field, its header name and how its value is written
+type cwgameField struct {
+	header string
+	value  func(row *cwgameRow) string
+}
+
+// cwgameCodes the numeric codes cwgame writes for the weather and scoring details, 0 being unknown
+var cwgameCodes = map[string]map[string]int{
+	"winddir":   {"tolf": 1, "tocf": 2, "torf": 3, "ltor": 4, "fromlf": 5, "fromcf": 6, "fromrf": 7, "rtol": 8},
+	"fieldcond": {"soaked": 1, "wet": 2, "damp": 3, "dry": 4},
+	"precip":    {"none": 1, "drizzle": 2, "showers": 3, "rain": 4, "snow": 5},
+	"sky":       {"sunny": 1, "cloudy": 2, "overcast": 3, "night": 4, "dome": 5},
+	"howscored": {"park": 1, "tv": 2, "radio": 3},
+	"pitches":   {"count": 1, "pitches": 2},
+}
+
+// cwgameCode the numeric code of a recorded value, 0 when it wasn't recorded
+func cwgameCode(kind string, value *string) string {
+	if value == nil {
+		return "0"
+	}
+
+	return cweventNumber(cwgameCodes[kind][strings.ToLower(*value)])
+}
+
+// cwgameOptionalText a quoted text value, blank when it wasn't recorded
+func cwgameOptionalText(value *string) string {
+	if value == nil {
+		return cweventText("")
+	}
+
+	return cweventText(*value)
+}
+
+// cwgameOptionalNumber an unquoted number, the given unknown value when it wasn't recorded
+func cwgameOptionalNumber(value *int, unknown int) string {
+	if value == nil {
+		return cweventNumber(unknown)
+	}
+
+	return cweventNumber(*value)
+}
+
+// gameNumber the number event files give a game, 0 for a single game and 1 or 2 for a doubleheader
+func gameNumber(gameType string) int {
+	for number, name := range GameTypes {
+		if name == gameType {
+			return number
+		}
+	}
+
+	return 0
+}
+
+// linescoreLine writes each inning's runs the way cwgame does, innings of ten runs or more in parentheses and an x for an inning not batted
+func linescoreLine(linescore Linescore, team int) string {
+	b := strings.Builder{}
+	for _, runs := range linescore.Innings[team] {
+		if runs >= 10 {
+			b.WriteString(fmt.Sprintf("(%d)", runs))
+		} else {
+			b.WriteString(strconv.Itoa(runs))
+		}
+	}
+	if team == 1 && len(linescore.Innings[1]) < len(linescore.Innings[0]) {
+		b.WriteString("x")
+	}
+
+	return b.String()
+}
+
+// starter the player starting in a team's batting order slot (1-9), or the starting pitcher for slot 0
+func (r *cwgameRow) starter(team int, slot int) Player {
+	players := r.game.Lineup.Visitor
+	if team == 1 {
+		players = r.game.Lineup.Home
+	}
+
+	for _, player := range players {
+		if (slot == 0 && player.FieldingPosition.ID == "1") || (slot > 0 && player.BattingPosition == slot) {
+			return player
+		}
+	}
+
+	return Player{}
+}
+
+// finisher the last pitcher a team used
+func (r *cwgameRow) finisher(team int) string {
+	for i := len(r.game.Plays) - 1; i >= 0; i-- {
+		if r.game.Plays[i].Team == 1-team {
+			return r.game.Plays[i].PitcherID
+		}
+	}
+
+	return r.starter(team, 0).ID
+}
+
+// cwgameLineupFields the batter and fielding position fields of each starting lineup slot, AWAY_LINEUP1_BAT_ID and so on
+func cwgameLineupFields(team int) []cwgameField {
+	fields := make([]cwgameField, 0)
+	side := []string{"AWAY", "HOME"}[team]
+
+	for slot := 1; slot <= 9; slot++ {
+		slot := slot
+		fields = append(fields,
+			cwgameField{fmt.Sprintf("%s_LINEUP%d_BAT_ID", side, slot), func(r *cwgameRow) string { return cweventText(r.starter(team, slot).ID) }},
+			cwgameField{fmt.Sprintf("%s_LINEUP%d_FLD_CD", side, slot), func(r *cwgameRow) string {
+				position, _ := strconv.Atoi(r.starter(team, slot).FieldingPosition.ID)
+				return cweventNumber(position)
+			}},
+		)
+	}
+
+	return fields
+}
+
+// cwgameFields the 84 standard cwgame fields, in cwgame's numbering
+var cwgameFields = append(append(append([]cwgameField{
+	{"GAME_ID", func(r *cwgameRow) string { return cweventText(r.game.ID) }},
+	{"GAME_DT", func(r *cwgameRow) string {
+		if r.game.Info.Date.IsZero() {
+			return cweventText("")
+		}
+		return cweventText(r.game.Info.Date.Format("060102"))
+	}},
+	{"GAME_CT", func(r *cwgameRow) string { return cweventNumber(gameNumber(r.game.Info.GameType)) }},
+	{"GAME_DY", func(r *cwgameRow) string {
+		if r.game.Info.Date.IsZero() {
+			return cweventText("")
+		}
+		return cweventText(r.game.Info.Date.Weekday().String())
+	}},
+	{"START_GAME_TM", func(r *cwgameRow) string {
+		start := r.game.Info.Starttime
+		if start == nil {
+			return "0"
+		}
+		hour := start.Hour % 12
+		if hour == 0 {
+			hour = 12
+		}
+		return cweventNumber(hour*100 + start.Minute)
+	}},
+	{"DH_FL", func(r *cwgameRow) string { return cweventFlag(r.game.Info.Usedh) }},
+	{"DAYNIGHT_PARK_CD", func(r *cwgameRow) string {
+		if r.game.Info.Daynight == nil {
+			return cweventText("")
+		}
+		return cweventText((*r.game.Info.Daynight)[:1])
+	}},
+	{"AWAY_TEAM_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Visteam.ID) }},
+	{"HOME_TEAM_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Hometeam.ID) }},
+	{"PARK_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Site.ID) }},
+	{"AWAY_START_PIT_ID", func(r *cwgameRow) string { return cweventText(r.starter(0, 0).ID) }},
+	{"HOME_START_PIT_ID", func(r *cwgameRow) string { return cweventText(r.starter(1, 0).ID) }},
+	{"BASE4_UMP_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Umphome.ID) }},
+	{"BASE1_UMP_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Ump1b.ID) }},
+	{"BASE2_UMP_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Ump2b.ID) }},
+	{"BASE3_UMP_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Ump3b.ID) }},
+	{"LF_UMP_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.UmpLF.ID) }},
+	{"RF_UMP_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.UmpRF.ID) }},
+	{"ATTEND_PARK_CT", func(r *cwgameRow) string { return cwgameOptionalNumber(r.game.Info.Attendance, 0) }},
+	{"SCORER_RECORD_ID", func(r *cwgameRow) string { return cwgameOptionalText(r.game.Info.Scorer) }},
+	{"TRANSLATOR_RECORD_ID", func(r *cwgameRow) string { return cwgameOptionalText(r.game.Info.Translator) }},
+	{"INPUTTER_RECORD_ID", func(r *cwgameRow) string { return cwgameOptionalText(r.game.Info.Inputter) }},
+	{"INPUT_RECORD_TS", func(r *cwgameRow) string { return cwgameOptionalText(r.game.Info.Inputtime) }},
+	{"EDIT_RECORD_TS", func(r *cwgameRow) string { return cwgameOptionalText(r.game.Info.Edittime) }},
+	{"METHOD_RECORD_CD", func(r *cwgameRow) string { return cwgameCode("howscored", r.game.Info.Howscored) }},
+	{"PITCHES_RECORD_CD", func(r *cwgameRow) string { return cwgameCode("pitches", r.game.Info.Pitches) }},
+	{"TEMP_PARK_CT", func(r *cwgameRow) string { return cwgameOptionalNumber(r.game.Info.Temp, 0) }},
+	{"WIND_DIRECTION_PARK_CD", func(r *cwgameRow) string {
+		return cweventNumber(cwgameCodes["winddir"][string(r.game.Info.Winddir)])
+	}},
+	{"WIND_SPEED_PARK_CT", func(r *cwgameRow) string { return cwgameOptionalNumber(r.game.Info.Windspeed, -1) }},
+	{"FIELD_PARK_CD", func(r *cwgameRow) string {
+		return cweventNumber(cwgameCodes["fieldcond"][string(r.game.Info.Fieldcond)])
+	}},
+	{"PRECIP_PARK_CD", func(r *cwgameRow) string {
+		return cweventNumber(cwgameCodes["precip"][string(r.game.Info.Precip)])
+	}},
+	{"SKY_PARK_CD", func(r *cwgameRow) string { return cweventNumber(cwgameCodes["sky"][string(r.game.Info.Sky)]) }},
+	{"MINUTES_GAME_CT", func(r *cwgameRow) string { return cwgameOptionalNumber(r.game.Info.Timeofgame, 0) }},
+	{"INN_CT", func(r *cwgameRow) string { return cweventNumber(len(r.linescore.Innings[0])) }},
+	{"AWAY_SCORE_CT", func(r *cwgameRow) string { return cweventNumber(r.linescore.Runs[0]) }},
+	{"HOME_SCORE_CT", func(r *cwgameRow) string { return cweventNumber(r.linescore.Runs[1]) }},
+	{"AWAY_HITS_CT", func(r *cwgameRow) string { return cweventNumber(r.linescore.Hits[0]) }},
+	{"HOME_HITS_CT", func(r *cwgameRow) string { return cweventNumber(r.linescore.Hits[1]) }},
+	{"AWAY_ERR_CT", func(r *cwgameRow) string { return cweventNumber(r.linescore.Errors[0]) }},
+	{"HOME_ERR_CT", func(r *cwgameRow) string { return cweventNumber(r.linescore.Errors[1]) }},
+	{"AWAY_LOB_CT", func(r *cwgameRow) string { return cweventNumber(r.linescore.LeftOnBase[0]) }},
+	{"HOME_LOB_CT", func(r *cwgameRow) string { return cweventNumber(r.linescore.LeftOnBase[1]) }},
+	{"WIN_PIT_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Wp.ID) }},
+	{"LOSE_PIT_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Lp.ID) }},
+	{"SAVE_PIT_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Save.ID) }},
+	{"GWRBI_BAT_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Gwrbi.ID) }},
+}, cwgameLineupFields(0)...), cwgameLineupFields(1)...),
+	cwgameField{"AWAY_FINISH_PIT_ID", func(r *cwgameRow) string { return cweventText(r.finisher(0)) }},
+	cwgameField{"HOME_FINISH_PIT_ID", func(r *cwgameRow) string { return cweventText(r.finisher(1)) }},
+)
+
+// cwgameExtendedFields the first of cwgame's extended fields, picked with -x, which carry the leagues and divisions,
+// team game counts and linescores. They're numbered the same as cwgame's.
+var cwgameExtendedFields = []cwgameField{
+	{"AWAY_TEAM_LEAGUE_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Visteam.League.ID) }},
+	{"HOME_TEAM_LEAGUE_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Hometeam.League.ID) }},
+	{"AWAY_TEAM_DIV_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Visteam.Division) }},
+	{"HOME_TEAM_DIV_ID", func(r *cwgameRow) string { return cweventText(r.game.Info.Hometeam.Division) }},
+	{"AWAY_TEAM_GAME_CT", func(r *cwgameRow) string { return cweventNumber(r.teamGames[0]) }},
+	{"HOME_TEAM_GAME_CT", func(r *cwgameRow) string { return cweventNumber(r.teamGames[1]) }},
+	{"OUTS_CT", func(r *cwgameRow) string {
+		outs := 0
+		for _, play := range r.game.Plays {
+			outs += play.After.Outs - play.Before.Outs
+		}
+		return cweventNumber(outs)
+	}},
+	// completion, forfeit and protest records aren't read from event files yet
+	{"COMPLETION_TX", func(r *cwgameRow) string { return cweventText("") }},
+	{"FORFEIT_TX", func(r *cwgameRow) string { return cweventText("") }},
+	{"PROTEST_TX", func(r *cwgameRow) string { return cweventText("") }},
+	{"AWAY_LINE_TX", func(r *cwgameRow) string { return cweventText(linescoreLine(r.linescore, 0)) }},
+	{"HOME_LINE_TX", func(r *cwgameRow) string { return cweventText(linescoreLine(r.linescore, 1)) }},
+}
+
+// CreateGameCSV Given games and the cwgame standard and extended field numbers to write, return one line per game in cwgame's csv format,
+// with a header line of field names first when header is set
+func CreateGameCSV(games []Game, fields []int, extended []int, header bool) string {
+	b := strings.Builder{}
+
+	if header {
+		names := make([]string, 0)
+		for _, field := range fields {
+			names = append(names, cweventText(cwgameFields[field].header))
+		}
+		for _, field := range extended {
+			names = append(names, cweventText(cwgameExtendedFields[field].header))
+		}
+		b.WriteString(strings.Join(names, ",") + "\n")
+	}
+
+	teamGames := make(map[string]int)
+	for i := range games {
+		game := &games[i]
+		teamGames[game.Info.Visteam.ID]++
+		teamGames[game.Info.Hometeam.ID]++
+		row := cwgameRow{
+			game:      game,
+			linescore: CreateLinescore(game.Plays),
+			teamGames: [2]int{teamGames[game.Info.Visteam.ID], teamGames[game.Info.Hometeam.ID]},
+		}
+
+		values := make([]string, 0)
+		for _, field := range fields {
+			values = append(values, cwgameFields[field].value(&row))
+		}
+		for _, field := range extended {
+			values = append(values, cwgameExtendedFields[field].value(&row))
+		}
+		b.WriteString(strings.Join(values, ",") + "\n")
+	}
+
+	return b.String()
+}
+
+// writeGameCSV writes the games in cwgame's csv format to the given file
+func writeGameCSV(outputPath string, games []Game, fields []int, extended []int, header bool) error {
+	return ioutil.WriteFile(outputPath, []byte(CreateGameCSV(games, fields, extended, header)), 0644)
+}
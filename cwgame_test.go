@@ -0,0 +1,91 @@
+package main
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestCreateGameCSVFieldNumbers(t *testing.T) {
+	tests := []struct {
+		extended bool
+		field    int
+		header   string
+	}{
+		{false, 0, "GAME_ID"},
+		{false, 12, "BASE4_UMP_ID"},
+		{false, 16, "LF_UMP_ID"},
+		{false, 17, "RF_UMP_ID"},
+		{false, 20, "TRANSLATOR_RECORD_ID"},
+		{false, 22, "INPUT_RECORD_TS"},
+		{false, 23, "EDIT_RECORD_TS"},
+		{false, 33, "INN_CT"},
+		{false, 45, "GWRBI_BAT_ID"},
+		{false, 46, "AWAY_LINEUP1_BAT_ID"},
+		{false, 63, "AWAY_LINEUP9_FLD_CD"},
+		{false, 64, "HOME_LINEUP1_BAT_ID"},
+		{false, 81, "HOME_LINEUP9_FLD_CD"},
+		{false, 82, "AWAY_FINISH_PIT_ID"},
+		{false, 83, "HOME_FINISH_PIT_ID"},
+		{true, 0, "AWAY_TEAM_LEAGUE_ID"},
+		{true, 1, "HOME_TEAM_LEAGUE_ID"},
+		{true, 2, "AWAY_TEAM_DIV_ID"},
+		{true, 3, "HOME_TEAM_DIV_ID"},
+		{true, 4, "AWAY_TEAM_GAME_CT"},
+		{true, 5, "HOME_TEAM_GAME_CT"},
+		{true, 6, "OUTS_CT"},
+		{true, 7, "COMPLETION_TX"},
+		{true, 8, "FORFEIT_TX"},
+		{true, 9, "PROTEST_TX"},
+		{true, 10, "AWAY_LINE_TX"},
+		{true, 11, "HOME_LINE_TX"},
+	}
+
+	if len(cwgameFields) != 84 {
+		t.Errorf("want 84 standard fields, got %d", len(cwgameFields))
+	}
+	for _, test := range tests {
+		fields, extended := []int{test.field}, []int{}
+		if test.extended {
+			fields, extended = extended, fields
+		}
+		header := strings.Split(CreateGameCSV(nil, fields, extended, true), "\n")[0]
+		if header != `"`+test.header+`"` {
+			t.Errorf("extended %v field %d: want %s, got %s", test.extended, test.field, test.header, header)
+		}
+	}
+}
+
+func TestCreateGameCSVInfoFields(t *testing.T) {
+	data, err := ioutil.ReadFile(eventPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadTeams(1986, strings.NewReader("TSQ,N,Test,Squad\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadFranchises(strings.NewReader("TSQ,TSQ,NL,E,Test,Squad,,1/1/1969,,Test,NY\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first game with the visitors renamed to a team with a division and the umpires and records 1986 didn't have
+	game := make([]string, 0)
+	for _, record := range strings.Split(strings.Split(string(data), "\r\nid,")[0], "\r\n") {
+		switch record {
+		case "info,visteam,SLN":
+			record = "info,visteam,TSQ"
+		case "info,inputter,\"Zminda\"":
+			game = append(game, "info,translator,\"Ruane\"", record, "info,inputtime,2004/06/11 6:20PM", "info,edittime,2019/03/02 1:15PM")
+			continue
+		}
+		game = append(game, record)
+		if strings.HasPrefix(record, "info,ump3b,") {
+			game = append(game, "info,umplf,testu901", "info,umprf,testu902")
+		}
+	}
+
+	got := CreateGameCSV(CreateGames([]byte(strings.Join(game, "\r\n")+"\r\n")), []int{7, 16, 17, 20, 22, 23}, []int{2, 3}, false)
+	if want := `"TSQ","testu901","testu902","Ruane","2004/06/11 6:20PM","2019/03/02 1:15PM","E",""` + "\n"; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
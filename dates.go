@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	// park time zones are looked up without relying on the zone files of the machine running the conversion
+	_ "time/tzdata"
+)
+
+// Date a calendar day, written yyyy-mm-dd in json and null when it isn't known
+type Date struct {
+	time.Time
+}
+
+// ParseDate Given a date as written in event files (yyyy/mm/dd), return it as a Date, the zero Date when it can't be read
+func ParseDate(source string) Date {
+	t, err := time.Parse("2006/01/02", strings.TrimSpace(source))
+	if err != nil {
+		return Date{}
+	}
+
+	return Date{t}
+}
+
+// String writes the date the way event files do
+func (date Date) String() string {
+	if date.IsZero() {
+		return "unknown date"
+	}
+
+	return date.Format("2006/01/02")
+}
+
+// number the date as a yyyymmdd number for comparing against reference data, 0 when it isn't known
+func (date Date) number() int {
+	if date.IsZero() {
+		return 0
+	}
+
+	return date.Year()*10000 + int(date.Month())*100 + date.Day()
+}
+
+func (date Date) MarshalJSON() ([]byte, error) {
+	if date.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(date.Format("2006-01-02"))
+}
+
+func (date *Date) UnmarshalJSON(data []byte) error {
+	var source *string
+	if err := json.Unmarshal(data, &source); err != nil {
+		return err
+	}
+	if source == nil {
+		*date = Date{}
+		return nil
+	}
+
+	t, err := time.Parse("2006-01-02", *source)
+	if err != nil {
+		return err
+	}
+	*date = Date{t}
+
+	return nil
+}
+
+// ClockTime a local time of day, written hh:mm on a 24 hour clock in json
+type ClockTime struct {
+	Hour   int
+	Minute int
+}
+
+// startTimeLayouts the ways event files write the start time of a game
+var startTimeLayouts = []string{"3:04PM", "3:04 PM", "15:04"}
+
+// ParseStartTime Given a start time from an event file, return it as a ClockTime.
+// Event files write 0:00PM or leave the time blank when it isn't known, and those give nil.
+func ParseStartTime(source string) *ClockTime {
+	source = strings.ToUpper(strings.TrimSpace(source))
+
+	for _, layout := range startTimeLayouts {
+		t, err := time.Parse(layout, source)
+		if err != nil {
+			continue
+		}
+		// the unknown marker 0:00PM parses as noon, so look at how it was written
+		if strings.HasPrefix(source, "0:") || (t.Hour() == 0 && t.Minute() == 0) {
+			return nil
+		}
+
+		return &ClockTime{Hour: t.Hour(), Minute: t.Minute()}
+	}
+
+	return nil
+}
+
+// String writes the time the way box scores do, e.g. 7:35 PM
+func (clock ClockTime) String() string {
+	hour := clock.Hour % 12
+	if hour == 0 {
+		hour = 12
+	}
+	half := "AM"
+	if clock.Hour >= 12 {
+		half = "PM"
+	}
+
+	return fmt.Sprintf("%d:%02d %s", hour, clock.Minute, half)
+}
+
+func (clock ClockTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fmt.Sprintf("%02d:%02d", clock.Hour, clock.Minute))
+}
+
+func (clock *ClockTime) UnmarshalJSON(data []byte) error {
+	var source string
+	if err := json.Unmarshal(data, &source); err != nil {
+		return err
+	}
+
+	t, err := time.Parse("15:04", source)
+	if err != nil {
+		return err
+	}
+	*clock = ClockTime{Hour: t.Hour(), Minute: t.Minute()}
+
+	return nil
+}
+
+// gameTimestamp the moment a game started in its park's time zone, nil unless the date, start time and time zone are all known
+func gameTimestamp(date Date, start *ClockTime, park Park) *time.Time {
+	if date.IsZero() || start == nil || park.TimeZone == "" {
+		return nil
+	}
+
+	location, err := time.LoadLocation(park.TimeZone)
+	if err != nil {
+		return nil
+	}
+
+	t := time.Date(date.Year(), date.Month(), date.Day(), start.Hour, start.Minute, 0, 0, location)
+
+	return &t
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sizes used to lay out a drawn scorecard, in points
+const (
+	drawMargin       = 20
+	drawTitleHeight  = 50
+	drawInfoLine     = 14
+	drawHeaderHeight = 24
+	drawNameWidth    = 210
+	drawCellWidth    = 72
+	drawCellHeight   = 72
+	drawLineHeight   = 13
+	drawFooterRow    = 22
+	drawLinescoreRun = 28
+	drawDiamondSize  = 17
+)
+
+// canvas is something a scorecard can be drawn on, with coordinates running right and down from the top left
+type canvas interface {
+	Rect(x float64, y float64, width float64, height float64)
+	Polygon(points [][2]float64, fill string, stroke string, width float64)
+	Polyline(points [][2]float64, stroke string, width float64)
+	Circle(cx float64, cy float64, r float64, stroke string)
+	// Text draws a string anchored at its start, middle or end
+	Text(x float64, y float64, anchor string, size float64, bold bool, text string)
+}
+
+// ordinal formats an inning number as 1st, 2nd, 3rd...
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+// describeEntry labels a player on a scorecard with the side they bat from, their position and, for substitutes, when they came in
+func describeEntry(entry ScorecardPlayer) string {
+	label := fmt.Sprintf("%s %s", entry.Player.Name, entry.Player.FieldingPosition.Code)
+	if entry.Player.Bats != "" {
+		label = fmt.Sprintf("%s (%s) %s", entry.Player.Name, entry.Player.Bats, entry.Player.FieldingPosition.Code)
+	}
+	if entry.Inning > 0 {
+		label = fmt.Sprintf("%s (%s)", label, ordinal(entry.Inning))
+	}
+
+	return label
+}
+
+// describePitcher labels a pitcher on a scorecard with the hand they throw with and, for relievers, the inning they came in
+func describePitcher(entry ScorecardPlayer) string {
+	label := entry.Player.Name
+	if entry.Player.Throws != "" {
+		label = fmt.Sprintf("%s (%sHP)", label, entry.Player.Throws)
+	}
+	if entry.Inning > 0 {
+		label = fmt.Sprintf("%s (%s)", label, ordinal(entry.Inning))
+	}
+
+	return label
+}
+
+// describeUmpires lists the umpires of a game by base
+func describeUmpires(info Info, catalog Catalog) string {
+	umpires := make([]string, 0)
+	for _, ump := range []struct {
+		base   string
+		person Person
+	}{{"HP", info.Umphome}, {"1B", info.Ump1b}, {"2B", info.Ump2b}, {"3B", info.Ump3b}} {
+		if ump.person.ID != "" {
+			umpires = append(umpires, fmt.Sprintf("%s %s", ump.base, ump.person.FullName()))
+		}
+	}
+
+	return catalog.Sprintf("Umpires: %s", strings.Join(umpires, ", "))
+}
+
+// describeWeather summarizes the conditions a game was played in
+func describeWeather(info Info, catalog Catalog) string {
+	conditions := make([]string, 0)
+	if info.Temp != nil {
+		conditions = append(conditions, fmt.Sprintf("%d°F", *info.Temp))
+	}
+	if info.Windspeed != nil {
+		wind := catalog.Sprintf("wind %d mph", *info.Windspeed)
+		if info.Winddir != "" {
+			wind = fmt.Sprintf("%s %s", wind, strings.ToLower(info.Winddir.String()))
+		}
+		conditions = append(conditions, wind)
+	}
+	for _, condition := range []fmt.Stringer{info.Sky, info.Precip, info.Fieldcond} {
+		if condition.String() != "" {
+			conditions = append(conditions, strings.ToLower(condition.String()))
+		}
+	}
+
+	if len(conditions) == 0 {
+		return catalog.Text("Weather: not recorded")
+	}
+
+	return catalog.Sprintf("Weather: %s", strings.Join(conditions, ", "))
+}
+
+// describeGameInfo the lines of administrative information printed at the top of a scorecard
+func describeGameInfo(info Info, catalog Catalog) []string {
+	attendance := catalog.Text("Attendance: not recorded")
+	if info.Attendance != nil {
+		attendance = catalog.Sprintf("Attendance: %d", *info.Attendance)
+	}
+
+	timeOfGame := catalog.Text("Time: not recorded")
+	if info.Timeofgame != nil {
+		timeOfGame = catalog.Sprintf("Time: %d:%02d", *info.Timeofgame/60, *info.Timeofgame%60)
+	}
+
+	date := info.Date.String()
+	if info.Starttime != nil {
+		date = fmt.Sprintf("%s %s", date, info.Starttime)
+	}
+
+	return []string{
+		catalog.Sprintf("%s at %s, %s, %s, %s", info.Visteam.Name, info.Hometeam.Name, info.Site.Name, date, info.GameType),
+		fmt.Sprintf("%s   %s   %s", attendance, timeOfGame, describeWeather(info, catalog)),
+		describeUmpires(info, catalog),
+	}
+}
+
+// slotHeight how tall a row needs to be to list everyone who played in the slot
+func slotHeight(slot ScorecardSlot) float64 {
+	height := float64(drawLineHeight*len(slot.Players) + 10)
+	if height < drawCellHeight {
+		return drawCellHeight
+	}
+
+	return height
+}
+
+// scorecardSize the width and height needed to draw a team's scorecard, margins included
+func scorecardSize(game *Game, scorecard Scorecard, linescore Linescore) (float64, float64) {
+	gridHeight := float64(drawHeaderHeight + drawFooterRow)
+	for _, slot := range scorecard.Slots {
+		gridHeight += slotHeight(slot)
+	}
+
+	width := float64(drawNameWidth + len(scorecard.Columns)*drawCellWidth)
+	linescoreWidth := float64(drawNameWidth + (len(linescore.Innings[0])+3)*drawLinescoreRun)
+	if linescoreWidth > width {
+		width = linescoreWidth
+	}
+
+	height := drawTitleHeight + float64(len(describeGameInfo(game.Info, Catalogs["en"]))*drawInfoLine) + gridHeight + 6*drawFooterRow
+
+	return width + 2*drawMargin, height + 2*drawMargin
+}
+
+// drawDiamond draws the diamond for a plate appearance, tracing the bases the batter reached
+func drawDiamond(c canvas, cx float64, cy float64, box ScorecardBox) {
+	r := float64(drawDiamondSize)
+	points := [][2]float64{{cx, cy + r}, {cx + r, cy}, {cx, cy - r}, {cx - r, cy}, {cx, cy + r}}
+
+	fill := ""
+	if box.Bases == Home {
+		fill = "#999999"
+	}
+	c.Polygon(points[:4], fill, "#bbbbbb", 1)
+
+	if box.Bases > 0 {
+		c.Polyline(points[:box.Bases+1], "#000000", 3)
+	}
+}
+
+// drawBox draws one plate appearance in the cell at x, y
+func drawBox(c canvas, x float64, y float64, height float64, box ScorecardBox, catalog Catalog) {
+	cx := x + drawCellWidth/2
+	cy := y + height/2 - 4
+	drawDiamond(c, cx, cy, box)
+
+	if box.Out > 0 {
+		c.Circle(x+11, y+11, 8, "#cc0000")
+		c.Text(x+11, y+15, "middle", 10, true, fmt.Sprintf("%d", box.Out))
+	}
+
+	for i, note := range box.Notes {
+		c.Text(x+drawCellWidth-4, y+11+float64(i*9), "end", 8, false, note)
+	}
+
+	if box.RBI > 0 {
+		c.Text(x+4, y+height-18, "start", 8, false, catalog.Sprintf("%d RBI", box.RBI))
+	}
+
+	c.Text(cx, y+height-6, "middle", 11, true, box.Code)
+}
+
+// drawScorecard draws the scorecard of one team (0 visitor, 1 home) in a game, laid out for the size from scorecardSize,
+// with its labels in the language of the catalog
+func drawScorecard(c canvas, game *Game, scorecard Scorecard, linescore Linescore, catalog Catalog) {
+	x0 := float64(drawMargin)
+	y := float64(drawMargin)
+
+	c.Text(x0, y+20, "start", 18, true, scorecard.Name)
+	y += drawTitleHeight - 10
+	for _, line := range describeGameInfo(game.Info, catalog) {
+		c.Text(x0, y, "start", 10, false, line)
+		y += drawInfoLine
+	}
+	y += 10
+
+	c.Rect(x0, y, drawNameWidth, drawHeaderHeight)
+	c.Text(x0+6, y+16, "start", 11, true, catalog.Text("Batter"))
+	for i, inning := range scorecard.Columns {
+		x := x0 + drawNameWidth + float64(i*drawCellWidth)
+		c.Rect(x, y, drawCellWidth, drawHeaderHeight)
+		c.Text(x+drawCellWidth/2, y+16, "middle", 11, true, fmt.Sprintf("%d", inning))
+	}
+	y += drawHeaderHeight
+
+	for i, slot := range scorecard.Slots {
+		rowHeight := slotHeight(slot)
+		c.Rect(x0, y, drawNameWidth, rowHeight)
+		c.Text(x0+6, y+16, "start", 12, true, fmt.Sprintf("%d", i+1))
+		for j, entry := range slot.Players {
+			c.Text(x0+22, y+16+float64(j*drawLineHeight), "start", 11, j == 0, describeEntry(entry))
+		}
+
+		for col := range scorecard.Columns {
+			c.Rect(x0+drawNameWidth+float64(col*drawCellWidth), y, drawCellWidth, rowHeight)
+		}
+		for _, box := range slot.Boxes {
+			drawBox(c, x0+drawNameWidth+float64(box.Column*drawCellWidth), y, rowHeight, box, catalog)
+		}
+
+		y += rowHeight
+	}
+
+	c.Rect(x0, y, drawNameWidth, drawFooterRow)
+	c.Text(x0+6, y+15, "start", 11, true, catalog.Text("R / H"))
+	for col, inning := range scorecard.Columns {
+		x := x0 + drawNameWidth + float64(col*drawCellWidth)
+		c.Rect(x, y, drawCellWidth, drawFooterRow)
+		if col > 0 && scorecard.Columns[col-1] == inning {
+			continue
+		}
+		for _, totals := range scorecard.Innings {
+			if totals.Inning == inning {
+				c.Text(x+drawCellWidth/2, y+15, "middle", 11, false, fmt.Sprintf("%d / %d", totals.Runs, totals.Hits))
+			}
+		}
+	}
+	y += drawFooterRow
+
+	pitchers := make([]string, 0)
+	for _, pitcher := range scorecard.Pitchers {
+		pitchers = append(pitchers, describePitcher(pitcher))
+	}
+	c.Text(x0, y+16, "start", 11, false, catalog.Sprintf("Pitchers: %s", strings.Join(pitchers, ", ")))
+	y += drawFooterRow * 2
+
+	drawLinescore(c, x0, y, game, linescore, catalog)
+}
+
+// drawLinescore draws the linescore for both teams with the top left corner at x, y
+func drawLinescore(c canvas, x0 float64, y float64, game *Game, linescore Linescore, catalog Catalog) {
+	innings := len(linescore.Innings[0])
+	headers := make([]string, 0)
+	for inning := 1; inning <= innings; inning++ {
+		headers = append(headers, fmt.Sprintf("%d", inning))
+	}
+	headers = append(headers, catalog.Text("R"), catalog.Text("H"), catalog.Text("E"))
+
+	c.Rect(x0, y, drawNameWidth, drawFooterRow)
+	for i, header := range headers {
+		x := x0 + drawNameWidth + float64(i*drawLinescoreRun)
+		c.Rect(x, y, drawLinescoreRun, drawFooterRow)
+		c.Text(x+drawLinescoreRun/2, y+15, "middle", 11, true, header)
+	}
+
+	for team, name := range []string{game.Info.Visteam.Name, game.Info.Hometeam.Name} {
+		rowY := y + float64((team+1)*drawFooterRow)
+		c.Rect(x0, rowY, drawNameWidth, drawFooterRow)
+		c.Text(x0+6, rowY+15, "start", 11, true, name)
+
+		for i, value := range linescoreRow(linescore, team) {
+			x := x0 + drawNameWidth + float64(i*drawLinescoreRun)
+			c.Rect(x, rowY, drawLinescoreRun, drawFooterRow)
+			c.Text(x+drawLinescoreRun/2, rowY+15, "middle", 11, false, value)
+		}
+	}
+}
+
+// linescoreRow the runs by inning for a team followed by their runs, hits and errors, with an X for an inning they didn't need to bat
+func linescoreRow(linescore Linescore, team int) []string {
+	values := make([]string, 0)
+	for inning := 0; inning < len(linescore.Innings[0]); inning++ {
+		if inning < len(linescore.Innings[team]) {
+			values = append(values, fmt.Sprintf("%d", linescore.Innings[team][inning]))
+		} else {
+			values = append(values, "X")
+		}
+	}
+
+	return append(values, fmt.Sprintf("%d", linescore.Runs[team]), fmt.Sprintf("%d", linescore.Hits[team]), fmt.Sprintf("%d", linescore.Errors[team]))
+}
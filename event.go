@@ -0,0 +1,370 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Event types, numbered to match the event type codes used by Chadwick's cwevent
+const (
+	EventUnknown               = 0
+	EventNone                  = 1
+	EventGenericOut            = 2
+	EventStrikeout             = 3
+	EventStolenBase            = 4
+	EventDefensiveIndifference = 5
+	EventCaughtStealing        = 6
+	EventPickoffError          = 7
+	EventPickoff               = 8
+	EventWildPitch             = 9
+	EventPassedBall            = 10
+	EventBalk                  = 11
+	EventOtherAdvance          = 12
+	EventFoulError             = 13
+	EventWalk                  = 14
+	EventIntentionalWalk       = 15
+	EventHitByPitch            = 16
+	EventInterference          = 17
+	EventError                 = 18
+	EventFieldersChoice        = 19
+	EventSingle                = 20
+	EventDouble                = 21
+	EventTriple                = 22
+	EventHomeRun               = 23
+	EventMissingPlay           = 24
+)
+
+// Home is the base number used for a runner crossing the plate
+const Home = 4
+
+// Advance describes where the batter (From 0) or a runner (From 1-3) ended up on a play
+type Advance struct {
+	From     int  `json:"from"`
+	To       int  `json:"to"`
+	Out      bool `json:"out"`
+	NoRBI    bool `json:"noRbi,omitempty"`
+	RBI      bool `json:"rbi,omitempty"`
+	Unearned bool `json:"unearned,omitempty"`
+}
+
+// Event is the parsed meaning of the event field of a play record
+type Event struct {
+	Source     string    `json:"source"`
+	BasicPlay  string    `json:"basicPlay"`
+	Modifiers  []string  `json:"modifiers,omitempty"`
+	Type       int       `json:"type"`
+	Advances   []Advance `json:"advances,omitempty"`
+	Errors     []int     `json:"errors,omitempty"`
+	Sacrifice  bool      `json:"sacrifice,omitempty"`
+	SacFly     bool      `json:"sacFly,omitempty"`
+	DoublePlay bool      `json:"doublePlay,omitempty"`
+	TriplePlay bool      `json:"triplePlay,omitempty"`
+}
+
+// BaseNumber converts a base code from the event notation (B, 1, 2, 3, H) to a base number
+func BaseNumber(code string) int {
+	switch code {
+	case "B":
+		return 0
+	case "H":
+		return Home
+	}
+
+	base, _ := strconv.Atoi(code)
+	return base
+}
+
+// BaseCode converts a base number back to its code in the event notation
+func BaseCode(base int) string {
+	switch base {
+	case 0:
+		return "B"
+	case Home:
+		return "H"
+	}
+
+	return strconv.Itoa(base)
+}
+
+var (
+	strikeoutMatcher    = regexp.MustCompile("^K(\\d*)$")
+	hitMatcher          = regexp.MustCompile("^(S|D|T|HR|H|DGR)(\\d*)$")
+	errorMatcher        = regexp.MustCompile("^E(\\d)$")
+	foulErrorMatcher    = regexp.MustCompile("^FLE(\\d)$")
+	fieldersChoice      = regexp.MustCompile("^FC(\\d*)$")
+	stolenBaseMatcher   = regexp.MustCompile("^SB([23H])$")
+	caughtStealing      = regexp.MustCompile("^(PO)?CS([23H])(?:\\((.*)\\))?$")
+	pickoffMatcher      = regexp.MustCompile("^PO([123])(?:\\((.*)\\))?$")
+	fieldingOutMatcher  = regexp.MustCompile("^[1-9][1-9E()B123]*$")
+	fieldingOutSegments = regexp.MustCompile("([1-9E]+)(?:\\(([B123])\\))?")
+	advanceMatcher      = regexp.MustCompile("^([B123])([-X])([123H])(.*)$")
+	errorCredits        = regexp.MustCompile("E([1-9])")
+)
+
+// ParseEvent Given the event field of a play record, return the parsed Event.
+// Runners forced along by the batter that are not mentioned in the record are moved when the event is applied to a GameState.
+func ParseEvent(source string) (Event, error) {
+	event := Event{Source: source}
+	play := strings.SplitN(source, ".", 2)
+	batterEvent := strings.Split(play[0], "/")
+	event.BasicPlay = batterEvent[0]
+	event.Modifiers = batterEvent[1:]
+
+	for _, modifier := range event.Modifiers {
+		switch modifier {
+		case "SH":
+			event.Sacrifice = true
+		case "SF":
+			event.SacFly = true
+		case "DP", "GDP", "LDP", "FDP", "BGDP", "BPDP":
+			event.DoublePlay = true
+		case "TP", "GTP", "LTP":
+			event.TriplePlay = true
+		}
+	}
+
+	for _, credit := range errorCredits.FindAllStringSubmatch(source, -1) {
+		position, _ := strconv.Atoi(credit[1])
+		event.Errors = append(event.Errors, position)
+	}
+
+	implied := make(map[int]Advance)
+	parts := strings.SplitN(event.BasicPlay, "+", 2)
+
+	if err := parsePrimaryPlay(&event, parts[0], implied); err != nil {
+		return event, err
+	}
+
+	if len(parts) == 2 {
+		for _, secondary := range strings.Split(parts[1], ";") {
+			if err := parseRunnerPlay(&event, secondary, implied, true); err != nil {
+				return event, err
+			}
+		}
+	}
+
+	if len(play) == 2 {
+		for _, v := range strings.Split(play[1], ";") {
+			advanceMatch := advanceMatcher.FindStringSubmatch(v)
+			if advanceMatch == nil {
+				return event, fmt.Errorf("unrecognized runner advance '%s'", v)
+			}
+
+			advance := Advance{
+				From: BaseNumber(advanceMatch[1]),
+				To:   BaseNumber(advanceMatch[3]),
+				Out:  advanceMatch[2] == "X",
+			}
+			notes := advanceMatch[4]
+
+			// an error in the fielding notes of an out means the runner was safe after all
+			if advance.Out && errorCredits.MatchString(notes) {
+				advance.Out = false
+			}
+			advance.NoRBI = strings.Contains(notes, "(NR)") || strings.Contains(notes, "(NORBI)")
+			advance.RBI = strings.Contains(notes, "(RBI)")
+			advance.Unearned = strings.Contains(notes, "(UR)") || strings.Contains(notes, "(TUR)")
+
+			implied[advance.From] = advance
+		}
+	}
+
+	for from := 0; from <= 3; from++ {
+		if advance, ok := implied[from]; ok {
+			event.Advances = append(event.Advances, advance)
+		}
+	}
+
+	return event, nil
+}
+
+// parsePrimaryPlay handles the part of the basic play before any "+"
+func parsePrimaryPlay(event *Event, play string, implied map[int]Advance) error {
+	switch {
+	case play == "NP":
+		event.Type = EventNone
+	case play == "W":
+		event.Type = EventWalk
+		implied[0] = Advance{From: 0, To: 1}
+	case play == "IW" || play == "I":
+		event.Type = EventIntentionalWalk
+		implied[0] = Advance{From: 0, To: 1}
+	case play == "HP":
+		event.Type = EventHitByPitch
+		implied[0] = Advance{From: 0, To: 1}
+	case play == "C":
+		event.Type = EventInterference
+		implied[0] = Advance{From: 0, To: 1}
+	case strikeoutMatcher.MatchString(play):
+		event.Type = EventStrikeout
+		implied[0] = Advance{From: 0, To: 1, Out: true}
+	case hitMatcher.MatchString(play):
+		switch hitMatcher.FindStringSubmatch(play)[1] {
+		case "S":
+			event.Type = EventSingle
+			implied[0] = Advance{From: 0, To: 1}
+		case "D", "DGR":
+			event.Type = EventDouble
+			implied[0] = Advance{From: 0, To: 2}
+		case "T":
+			event.Type = EventTriple
+			implied[0] = Advance{From: 0, To: 3}
+		default:
+			event.Type = EventHomeRun
+			implied[0] = Advance{From: 0, To: Home}
+		}
+	case errorMatcher.MatchString(play):
+		event.Type = EventError
+		implied[0] = Advance{From: 0, To: 1}
+	case foulErrorMatcher.MatchString(play):
+		event.Type = EventFoulError
+	case fieldersChoice.MatchString(play):
+		event.Type = EventFieldersChoice
+		implied[0] = Advance{From: 0, To: 1}
+	case fieldingOutMatcher.MatchString(play):
+		parseFieldingPlay(event, play, implied)
+	default:
+		return parseRunnerPlay(event, play, implied, false)
+	}
+
+	return nil
+}
+
+// parseFieldingPlay handles sequences of fielders such as 63, 64(1)3, 54(1) or 4(B)3(1)
+func parseFieldingPlay(event *Event, play string, implied map[int]Advance) {
+	if strings.Contains(play, "E") {
+		event.Type = EventError
+		implied[0] = Advance{From: 0, To: 1}
+		return
+	}
+
+	event.Type = EventGenericOut
+	batterOut := false
+	segments := fieldingOutSegments.FindAllStringSubmatch(play, -1)
+
+	for i, segment := range segments {
+		switch runner := segment[2]; runner {
+		case "":
+			// fielders not followed by a runner are recording the out on the batter
+			if i == len(segments)-1 {
+				batterOut = true
+			}
+		case "B":
+			batterOut = true
+		default:
+			from := BaseNumber(runner)
+			implied[from] = Advance{From: from, To: from + 1, Out: true}
+		}
+	}
+
+	if batterOut {
+		implied[0] = Advance{From: 0, To: 1, Out: true}
+	} else {
+		// a force out on a runner leaves the batter safe at first
+		implied[0] = Advance{From: 0, To: 1}
+	}
+}
+
+// parseRunnerPlay handles plays that do not involve the batter such as stolen bases and pickoffs
+func parseRunnerPlay(event *Event, play string, implied map[int]Advance, secondary bool) error {
+	setType := func(eventType int) {
+		if !secondary {
+			event.Type = eventType
+		}
+	}
+
+	switch {
+	case play == "WP":
+		setType(EventWildPitch)
+	case play == "PB":
+		setType(EventPassedBall)
+	case play == "BK":
+		setType(EventBalk)
+	case play == "DI":
+		setType(EventDefensiveIndifference)
+	case play == "OA":
+		setType(EventOtherAdvance)
+	case errorMatcher.MatchString(play):
+		// an error on a secondary play, any movement it causes is listed explicitly
+		setType(EventError)
+	case caughtStealing.MatchString(play):
+		matches := caughtStealing.FindStringSubmatch(play)
+		to := BaseNumber(matches[2])
+		setType(EventCaughtStealing)
+		if matches[1] == "PO" {
+			setType(EventPickoff)
+		}
+		implied[to-1] = Advance{From: to - 1, To: to, Out: !errorCredits.MatchString(matches[3])}
+	case pickoffMatcher.MatchString(play):
+		matches := pickoffMatcher.FindStringSubmatch(play)
+		base := BaseNumber(matches[1])
+		if errorCredits.MatchString(matches[2]) {
+			setType(EventPickoffError)
+		} else {
+			setType(EventPickoff)
+			implied[base] = Advance{From: base, To: base, Out: true}
+		}
+	case strings.HasPrefix(play, "SB"):
+		setType(EventStolenBase)
+		for _, steal := range strings.Split(play, ";") {
+			matches := stolenBaseMatcher.FindStringSubmatch(steal)
+			if matches == nil {
+				return fmt.Errorf("unrecognized stolen base '%s'", steal)
+			}
+			to := BaseNumber(matches[1])
+			implied[to-1] = Advance{From: to - 1, To: to}
+		}
+	default:
+		return fmt.Errorf("unrecognized play '%s'", play)
+	}
+
+	return nil
+}
+
+// IsPlateAppearance whether the event completes the batter's time at the plate
+func (event *Event) IsPlateAppearance() bool {
+	switch event.Type {
+	case EventGenericOut, EventStrikeout, EventWalk, EventIntentionalWalk, EventHitByPitch,
+		EventInterference, EventError, EventFieldersChoice, EventSingle, EventDouble, EventTriple, EventHomeRun:
+		return true
+	}
+
+	return false
+}
+
+// IsAtBat whether the plate appearance counts as an official at bat
+func (event *Event) IsAtBat() bool {
+	switch event.Type {
+	case EventWalk, EventIntentionalWalk, EventHitByPitch, EventInterference:
+		return false
+	}
+
+	return event.IsPlateAppearance() && !event.Sacrifice && !event.SacFly
+}
+
+// HitValue the number of bases on a hit, 0 for anything else
+func (event *Event) HitValue() int {
+	switch event.Type {
+	case EventSingle:
+		return 1
+	case EventDouble:
+		return 2
+	case EventTriple:
+		return 3
+	case EventHomeRun:
+		return 4
+	}
+
+	return 0
+}
+
+// AwardsRBI whether runs scoring on this event are credited to the batter, barring per-runner notes
+func (event *Event) AwardsRBI() bool {
+	if !event.IsPlateAppearance() || event.Type == EventError {
+		return false
+	}
+
+	return !event.DoublePlay
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// infoKeys the order info records are written in when a game doesn't say otherwise, such as one scored by hand
+var infoKeys = []string{
+	"visteam", "hometeam", "site", "date", "number", "starttime", "daynight", "usedh",
+	"umphome", "ump1b", "ump2b", "ump3b", "umplf", "umprf", "scorer", "translator", "inputter", "inputtime", "edittime",
+	"howscored", "pitches",
+	"temp", "winddir", "windspeed", "fieldcond", "precip", "sky", "timeofgame", "attendance",
+	"wp", "lp", "save", "gwrbi",
+}
+
+// optionalNumber writes a number that might not have been recorded, using the marker event files use for unknown
+func optionalNumber(value *int, unknown int) string {
+	if value == nil {
+		return strconv.Itoa(unknown)
+	}
+
+	return strconv.Itoa(*value)
+}
+
+// optionalText writes text that might not have been recorded, blank when it wasn't
+func optionalText(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+// infoValue the value of an info record as the event file writes it, blank for anything unknown
+func infoValue(info Info, key string) string {
+	switch key {
+	case "visteam":
+		return info.Visteam.ID
+	case "hometeam":
+		return info.Hometeam.ID
+	case "site":
+		return info.Site.ID
+	case "date":
+		if info.Date.IsZero() {
+			return ""
+		}
+		return info.Date.String()
+	case "number":
+		return strconv.Itoa(gameNumber(info.GameType))
+	case "starttime":
+		if info.Starttime == nil {
+			return ""
+		}
+		hour := info.Starttime.Hour % 12
+		if hour == 0 {
+			hour = 12
+		}
+		return fmt.Sprintf("%d:%02d%s", hour, info.Starttime.Minute, []string{"AM", "PM"}[info.Starttime.Hour/12])
+	case "daynight":
+		return strings.ToLower(optionalText(info.Daynight))
+	case "usedh":
+		return strconv.FormatBool(info.Usedh)
+	case "umphome":
+		return info.Umphome.ID
+	case "ump1b":
+		return info.Ump1b.ID
+	case "ump2b":
+		return info.Ump2b.ID
+	case "ump3b":
+		return info.Ump3b.ID
+	case "umplf":
+		return info.UmpLF.ID
+	case "umprf":
+		return info.UmpRF.ID
+	case "scorer":
+		return optionalText(info.Scorer)
+	case "translator":
+		return optionalText(info.Translator)
+	case "inputter":
+		return optionalText(info.Inputter)
+	case "inputtime":
+		return optionalText(info.Inputtime)
+	case "edittime":
+		return optionalText(info.Edittime)
+	case "howscored":
+		return optionalText(info.Howscored)
+	case "pitches":
+		return optionalText(info.Pitches)
+	case "temp":
+		return optionalNumber(info.Temp, 0)
+	case "winddir":
+		return string(info.Winddir)
+	case "windspeed":
+		return optionalNumber(info.Windspeed, -1)
+	case "fieldcond":
+		return string(info.Fieldcond)
+	case "precip":
+		return string(info.Precip)
+	case "sky":
+		return string(info.Sky)
+	case "timeofgame":
+		return optionalNumber(info.Timeofgame, 0)
+	case "attendance":
+		return optionalNumber(info.Attendance, 0)
+	case "wp":
+		return info.Wp.ID
+	case "lp":
+		return info.Lp.ID
+	case "save":
+		return info.Save.ID
+	case "gwrbi":
+		return info.Gwrbi.ID
+	}
+
+	return ""
+}
+
+// eventFileQuote wraps a value in quotes the way event files do for names and comments
+func eventFileQuote(value string) string {
+	return `"` + value + `"`
+}
+
+// eventInfoRecords writes the info records of a game. Each record the game was read with is written as it was unless its
+// field has since been changed, and fields set since that the file didn't have are added at the end.
+func eventInfoRecords(info Info) []string {
+	records := make([]string, 0)
+	written := make(map[string]bool)
+
+	for _, record := range info.Records {
+		value := record.Value
+		// reading the record on its own gives what the field held before any changes
+		if original := CreateInfo([]string{"info," + record.Key + "," + eventFileQuote(record.Value)}); infoValue(original, record.Key) != infoValue(info, record.Key) {
+			value = infoValue(info, record.Key)
+		}
+		if record.Quoted {
+			value = eventFileQuote(value)
+		}
+
+		records = append(records, fmt.Sprintf("info,%s,%s", record.Key, value))
+		written[record.Key] = true
+	}
+
+	for _, key := range infoKeys {
+		if written[key] || (len(info.Records) > 0 && infoValue(info, key) == infoValue(Info{}, key)) {
+			continue
+		}
+		value := infoValue(info, key)
+		if key == "scorer" || key == "translator" || key == "inputter" {
+			value = eventFileQuote(value)
+		}
+		records = append(records, fmt.Sprintf("info,%s,%s", key, value))
+	}
+
+	return records
+}
+
+// eventPlayerRecord writes a start or sub record for a player
+func eventPlayerRecord(recordType string, player Player, team int) string {
+	return fmt.Sprintf("%s,%s,%s,%d,%d,%s", recordType, player.ID, eventFileQuote(player.Name), team, player.BattingPosition, player.FieldingPosition.ID)
+}
+
+// eventComments writes com records
+func eventComments(comments []string) []string {
+	records := make([]string, 0)
+	for _, comment := range comments {
+		records = append(records, "com,"+eventFileQuote(comment))
+	}
+
+	return records
+}
+
+// eventDataRecords writes the earned run data records, pitchers in the order they appeared for the visitor then the home team
+func eventDataRecords(game *Game) []string {
+	records := make([]string, 0)
+	written := make(map[string]bool)
+	write := func(id string) {
+		if earned, ok := game.EarnedRuns[id]; ok && !written[id] {
+			records = append(records, fmt.Sprintf("data,er,%s,%d", id, earned))
+			written[id] = true
+		}
+	}
+
+	for team, lineup := range [][]Player{game.Lineup.Visitor, game.Lineup.Home} {
+		for _, player := range lineup {
+			if player.FieldingPosition.ID == "1" {
+				write(player.ID)
+			}
+		}
+		for _, play := range game.Plays {
+			for _, sub := range play.Substitutions {
+				if sub.Team == team && sub.Player.FieldingPosition.ID == "1" {
+					write(sub.Player.ID)
+				}
+			}
+		}
+	}
+
+	// anyone left over, such as a pitcher only in the data records, goes last
+	remaining := make([]string, 0)
+	for id := range game.EarnedRuns {
+		if !written[id] {
+			remaining = append(remaining, id)
+		}
+	}
+	sort.Strings(remaining)
+	for _, id := range remaining {
+		write(id)
+	}
+
+	return records
+}
+
+// eventRecords writes a game back out as the records of an event file
+func (game *Game) eventRecords() []string {
+	records := []string{"id," + game.ID}
+	if game.Version != "" {
+		records = append(records, "version,"+game.Version)
+	}
+	records = append(records, eventInfoRecords(game.Info)...)
+
+	for team, lineup := range [][]Player{game.Lineup.Visitor, game.Lineup.Home} {
+		for _, player := range lineup {
+			records = append(records, eventPlayerRecord("start", player, team))
+		}
+	}
+	records = append(records, eventComments(game.Comments)...)
+
+	for _, play := range game.Plays {
+		for _, sub := range play.Substitutions {
+			records = append(records, eventPlayerRecord("sub", sub.Player, sub.Team))
+			records = append(records, eventComments(sub.Comments)...)
+		}
+		records = append(records, play.Adjustments...)
+		records = append(records, fmt.Sprintf("play,%d,%d,%s,%s,%s,%s", play.Inning, play.Team, play.BatterID, play.Count, play.Pitches, play.Event.Source))
+		records = append(records, eventComments(play.Comments)...)
+	}
+	for _, sub := range game.TrailingSubstitutions {
+		records = append(records, eventPlayerRecord("sub", sub.Player, sub.Team))
+		records = append(records, eventComments(sub.Comments)...)
+	}
+	records = append(records, game.TrailingAdjustments...)
+
+	return append(records, eventDataRecords(game)...)
+}
+
+// CreateEventFile Given games, return them as a Retrosheet event file. Games read from an event file and left unchanged
+// are written byte for byte as they were read.
+func CreateEventFile(games []Game) string {
+	b := strings.Builder{}
+
+	for i := range games {
+		for _, record := range games[i].eventRecords() {
+			// event files end their lines the DOS way
+			b.WriteString(record + "\r\n")
+		}
+	}
+
+	return b.String()
+}
+
+// writeEventFile writes games to the given path as a Retrosheet event file
+func writeEventFile(outputPath string, games []Game) error {
+	return ioutil.WriteFile(outputPath, []byte(CreateEventFile(games)), 0644)
+}
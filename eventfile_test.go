@@ -0,0 +1,102 @@
+package main
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// firstDifference fails the test with the first line that differs between two event files
+func firstDifference(t *testing.T, want string, got string) {
+	t.Helper()
+	wantLines, gotLines := strings.Split(want, "\r\n"), strings.Split(got, "\r\n")
+	for i := 0; i < len(wantLines) && i < len(gotLines); i++ {
+		if wantLines[i] != gotLines[i] {
+			t.Fatalf("line %d: want %q, got %q", i+1, wantLines[i], gotLines[i])
+		}
+	}
+	t.Fatalf("want %d lines, got %d", len(wantLines), len(gotLines))
+}
+
+func TestCreateEventFileRoundTrip(t *testing.T) {
+	data, err := ioutil.ReadFile(eventPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if written := CreateEventFile(CreateGames(data)); written != string(data) {
+		firstDifference(t, string(data), written)
+	}
+}
+
+func TestCreateEventFileChangedInfo(t *testing.T) {
+	data, err := ioutil.ReadFile(eventPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	games := CreateGames(data)
+	original := strings.Split(CreateEventFile(games[:1]), "\r\n")
+
+	temp := 101
+	games[0].Info.Temp = &temp
+	changed := strings.Split(CreateEventFile(games[:1]), "\r\n")
+
+	if len(changed) != len(original) {
+		t.Fatalf("want %d lines, got %d", len(original), len(changed))
+	}
+	for i := range original {
+		switch {
+		case strings.HasPrefix(original[i], "info,temp,"):
+			if changed[i] != "info,temp,101" {
+				t.Errorf("line %d: want the changed temperature, got %q", i+1, changed[i])
+			}
+		case changed[i] != original[i]:
+			t.Errorf("line %d: want %q unchanged, got %q", i+1, original[i], changed[i])
+		}
+	}
+}
+
+func TestCreateEventFileCommentsAndAdjustments(t *testing.T) {
+	// plays with comments and an adjustment around them, and records after the last play
+	game := gameWithPlays(t,
+		`com,"before the first play"`,
+		"play,1,0,colev001,??,,K",
+		`com,"after a play"`,
+		"badj,mcgew001,R",
+		"play,1,0,mcgew001,??,,63",
+		`sub,orosj001,"Jesse Orosco",1,9,1`,
+		`com,"after a substitution"`,
+		"play,1,0,herrt001,??,,8",
+		`com,"after the last play"`,
+		`sub,hitte901,"Pinch Hitter",0,3,11`,
+		`com,"after a substitution past the last play"`,
+		"badj,clarj001,L",
+		"data,er,orosj001,0",
+	)
+
+	if written := CreateEventFile(CreateGames([]byte(game))); written != game {
+		firstDifference(t, game, written)
+	}
+}
+
+func TestCreateEventFileUnknownInfo(t *testing.T) {
+	data, err := ioutil.ReadFile(eventPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// info records from later seasons go after the first game's own
+	records := strings.Split(strings.Split(string(data), "\r\nid,")[0], "\r\n")
+	game := make([]string, 0)
+	for _, record := range records {
+		game = append(game, record)
+		if record == "info,usedh,false" {
+			game = append(game, "info,oscorer,smitj999", "info,htbf,false", "info,inputprogvers,\"version 7RS(19) of 07/07/92\"", "info,tiebreaker,2")
+		}
+	}
+	file := strings.Join(game, "\r\n") + "\r\n"
+
+	if written := CreateEventFile(CreateGames([]byte(file))); written != file {
+		firstDifference(t, file, written)
+	}
+}
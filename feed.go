@@ -0,0 +1,253 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// feedDefaultMinutes how long a game is taken to last when its time of game wasn't recorded
+const feedDefaultMinutes = 170
+
+// FeedEvent one message of a game feed: a play, a substitution, a play being taken back or the end of the game, along
+// with where the game stands after it
+type FeedEvent struct {
+	ID           int           `json:"id"`
+	Type         string        `json:"type"`
+	GameID       string        `json:"gameId"`
+	Inning       int           `json:"inning"`
+	Team         int           `json:"team"`
+	Play         *Play         `json:"play,omitempty"`
+	Substitution *Substitution `json:"substitution,omitempty"`
+	Situation    Situation     `json:"situation"`
+	Linescore    Linescore     `json:"linescore"`
+}
+
+// stateEvent an event of the given type carrying the state of the game after its plays
+func stateEvent(eventType string, game Game, plays []Play) FeedEvent {
+	event := FeedEvent{Type: eventType, GameID: game.ID, Linescore: CreateLinescore(plays)}
+	if len(plays) > 0 {
+		last := plays[len(plays)-1]
+		event.Inning, event.Team, event.Situation = last.Inning, last.Team, last.After
+	}
+
+	return event
+}
+
+// CreateFeedEvents Given a game, return the events of its feed from first pitch to the end of the game
+func CreateFeedEvents(game Game) []FeedEvent {
+	events := make([]FeedEvent, 0)
+	add := func(event FeedEvent) {
+		event.ID = len(events) + 1
+		events = append(events, event)
+	}
+
+	for i := range game.Plays {
+		play := game.Plays[i]
+		for j := range play.Substitutions {
+			event := stateEvent("substitution", game, game.Plays[:i])
+			event.Substitution = &play.Substitutions[j]
+			add(event)
+		}
+		event := stateEvent("play", game, game.Plays[:i+1])
+		event.Play = &play
+		add(event)
+	}
+	add(stateEvent("end", game, game.Plays))
+
+	return events
+}
+
+// feedPace how long each play of a game took on average, spreading the time of game over its plays
+func feedPace(game Game) time.Duration {
+	minutes := feedDefaultMinutes
+	if game.Info.Timeofgame != nil && *game.Info.Timeofgame > 0 {
+		minutes = *game.Info.Timeofgame
+	}
+	if len(game.Plays) == 0 {
+		return 0
+	}
+
+	return time.Duration(minutes) * time.Minute / time.Duration(len(game.Plays))
+}
+
+// FeedHub passes the events of games being scored live on to everyone subscribed to them
+type FeedHub struct {
+	mu sync.Mutex
+	// history holds every event published for a game so late subscribers can catch up
+	history     map[string][]FeedEvent
+	subscribers map[string]map[chan FeedEvent]bool
+}
+
+// CreateFeedHub Return a FeedHub with no games
+func CreateFeedHub() *FeedHub {
+	return &FeedHub{
+		history:     make(map[string][]FeedEvent),
+		subscribers: make(map[string]map[chan FeedEvent]bool),
+	}
+}
+
+// Publish numbers an event and sends it to the subscribers of its game. A subscriber too far behind is dropped rather
+// than holding up the scorer: its channel is closed so its stream ends, and the client reconnects with Last-Event-ID
+// to catch up from the history.
+func (hub *FeedHub) Publish(event FeedEvent) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	event.ID = len(hub.history[event.GameID]) + 1
+	hub.history[event.GameID] = append(hub.history[event.GameID], event)
+	for subscriber := range hub.subscribers[event.GameID] {
+		select {
+		case subscriber <- event:
+		default:
+			close(subscriber)
+			delete(hub.subscribers[event.GameID], subscriber)
+		}
+	}
+}
+
+// Live whether anything has been published for a game
+func (hub *FeedHub) Live(gameID string) bool {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	return len(hub.history[gameID]) > 0
+}
+
+// Subscribe Given a game, return the events published for it so far and a channel of the ones to come, which is closed
+// if the subscriber falls too far behind. Call the returned function to stop receiving them.
+func (hub *FeedHub) Subscribe(gameID string) ([]FeedEvent, chan FeedEvent, func()) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	subscriber := make(chan FeedEvent, 64)
+	if hub.subscribers[gameID] == nil {
+		hub.subscribers[gameID] = make(map[chan FeedEvent]bool)
+	}
+	hub.subscribers[gameID][subscriber] = true
+	past := append([]FeedEvent{}, hub.history[gameID]...)
+
+	return past, subscriber, func() {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		delete(hub.subscribers[gameID], subscriber)
+	}
+}
+
+// Drain waits for every subscriber to finish, as they do once they've sent the end of their game, giving up after timeout
+func (hub *FeedHub) Drain(timeout time.Duration) {
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		hub.mu.Lock()
+		subscribed := 0
+		for _, subscribers := range hub.subscribers {
+			subscribed += len(subscribers)
+		}
+		hub.mu.Unlock()
+
+		if subscribed == 0 {
+			return
+		}
+	}
+}
+
+// writeFeedEvent sends an event in the Server-Sent Events format
+func writeFeedEvent(w http.ResponseWriter, event FeedEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+		return err
+	}
+	w.(http.Flusher).Flush()
+
+	return nil
+}
+
+// handleFeed streams a game as Server-Sent Events. A game being scored live is sent as it happens, after catching up on
+// what's already been scored. Any other game is replayed, paced by its time of game and sped up by the speed parameter,
+// so speed=60 plays a three hour game in three minutes and speed=0 sends it all at once. Clients that reconnect with
+// Last-Event-ID pick up after the last event they saw.
+func (server *APIServer) handleFeed(w http.ResponseWriter, r *http.Request) {
+	if _, ok := w.(http.Flusher); !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming isn't supported"))
+		return
+	}
+	id := r.PathValue("id")
+	after, _ := strconv.Atoi(r.Header.Get("Last-Event-ID"))
+
+	speed := 1.0
+	if value := r.URL.Query().Get("speed"); value != "" {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("speed must be 0 or more"))
+			return
+		}
+		speed = n
+	}
+
+	live := server.feed != nil && server.feed.Live(id)
+	i, found := server.byID[id]
+	if !live && !found {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no game with id '%s'", id))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	if live {
+		past, events, stop := server.feed.Subscribe(id)
+		defer stop()
+		for _, event := range past {
+			if event.ID <= after {
+				continue
+			}
+			if err := writeFeedEvent(w, event); err != nil || event.Type == "end" {
+				return
+			}
+		}
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					// dropped for falling behind, the client picks up from here when it reconnects
+					return
+				}
+				if event.ID <= after {
+					continue
+				}
+				if err := writeFeedEvent(w, event); err != nil || event.Type == "end" {
+					return
+				}
+			}
+		}
+	}
+
+	game := server.games[i]
+	pace := time.Duration(0)
+	if speed > 0 {
+		pace = time.Duration(float64(feedPace(game)) / speed)
+	}
+	for _, event := range CreateFeedEvents(game) {
+		if event.ID <= after {
+			continue
+		}
+		if event.Type == "play" && pace > 0 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(pace):
+			}
+		}
+		if err := writeFeedEvent(w, event); err != nil {
+			return
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"strconv"
 	"strings"
 )
 
@@ -12,6 +13,18 @@ type Game struct {
 	ID     string `json:"id"`
 	Info   Info   `json:"info"`
 	Lineup Lineup `json:"lineup"`
+	Plays  []Play `json:"plays"`
+	// EarnedRuns holds the earned runs charged to each pitcher, from the data records at the end of the game
+	EarnedRuns map[string]int `json:"earnedRuns"`
+	// Version is the version record of the event file, and Comments the com records that come before the first play
+	Version  string   `json:"version"`
+	Comments []string `json:"comments,omitempty"`
+	// TrailingSubstitutions and TrailingAdjustments are the sub and adjustment records after the last play, as when a
+	// game was suspended partway through an inning
+	TrailingSubstitutions []Substitution `json:"trailingSubstitutions,omitempty"`
+	TrailingAdjustments   []string       `json:"trailingAdjustments,omitempty"`
+	// Language is the language the play descriptions are written in when it isn't English, see Localize
+	Language string `json:"language,omitempty"`
 }
 
 // Game.toJSON() Converts a Game struct to a json string
@@ -42,6 +55,9 @@ func CreateGame(gameData string) Game {
 	infoSource := make([]string, 0)
 	startSource := make([]string, 0)
 	playSource := make([]string, 0)
+	dataSource := make([]string, 0)
+	versionSource := make([]string, 0)
+	commentSource := make([]string, 0)
 
 	for _, record := range allRecords {
 		recordType := strings.Split(record, ",")[0]
@@ -53,23 +69,56 @@ func CreateGame(gameData string) Game {
 			infoSource = append(infoSource, record)
 		case "start":
 			startSource = append(startSource, record)
-		case "play":
+		case "version":
+			versionSource = append(versionSource, record)
+		case "com":
+			// comments are kept with the play or substitution they follow, once there is one
+			if len(playSource) == 0 {
+				commentSource = append(commentSource, record)
+			} else {
+				playSource = append(playSource, record)
+			}
+		case "play", "sub", "badj", "padj", "ladj", "radj", "presadj":
 			playSource = append(playSource, record)
+		case "data":
+			dataSource = append(dataSource, record)
 		}
 	}
 
 	idRecords := GetRecords(idSource)
 	info := CreateInfo(infoSource)
 	lineup := CreateLineup(GetRecords(startSource), info.Usedh)
+	plays, trailingSubs, trailingAdjustments := CreatePlays(playSource, lineup)
+	earnedRuns := make(map[string]int)
 
-	// @TODO this is temporary, used for analysis and testing ground for handling plays/runner advancements
-	processPlays(playSource)
+	for _, r := range GetRecords(dataSource) {
+		if r[1] == "er" {
+			earned, _ := strconv.Atoi(r[3])
+			earnedRuns[r[2]] = earned
+		}
+	}
 
 	game := Game{
-		ID:     idRecords[0][1],
-		Info:   info,
-		Lineup: lineup,
+		ID:         idRecords[0][1],
+		Info:       info,
+		Lineup:     lineup,
+		Plays:      plays,
+		EarnedRuns: earnedRuns,
+	}
+	for _, r := range GetRecords(versionSource) {
+		game.Version = r[1]
+	}
+	for _, r := range GetRecords(commentSource) {
+		game.Comments = append(game.Comments, r[1])
+	}
+	if len(trailingSubs) > 0 {
+		game.TrailingSubstitutions = trailingSubs
+	}
+	if len(trailingAdjustments) > 0 {
+		game.TrailingAdjustments = trailingAdjustments
 	}
+	game.joinRosters()
+	UnresolvedReferences.checkReferences(game)
 
 	return game
 }
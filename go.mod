@@ -0,0 +1,3 @@
+module github.com/bricemason/go-baseball-scorecard
+
+go 1.22
@@ -0,0 +1,472 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// htmlPlay what the viewer shows when an at-bat on the scorecard is clicked
+type htmlPlay struct {
+	Heading     string `json:"heading"`
+	Description string `json:"description"`
+	Source      string `json:"source"`
+	// Count, Before, After and Values are labelled lines, ready to show
+	Count   string   `json:"count"`
+	Pitches []string `json:"pitches"`
+	Before  string   `json:"before"`
+	After   string   `json:"after"`
+	Values  string   `json:"values"`
+}
+
+// htmlCell one cell of the scorecard grid, Play is -1 when the batter didn't come up in that column
+type htmlCell struct {
+	Play    int
+	Diagram template.HTML
+}
+
+// htmlRow a batting order slot on the scorecard grid
+type htmlRow struct {
+	Slot    int
+	Players []string
+	Height  float64
+	Cells   []htmlCell
+}
+
+// htmlScorecard a team's scorecard laid out for the page
+type htmlScorecard struct {
+	Name    string
+	Columns []int
+	Rows    []htmlRow
+	Totals  []string
+	// Pitchers is the labelled list of everyone who pitched
+	Pitchers string
+}
+
+// htmlLinescoreRow a team's row of the linescore
+type htmlLinescoreRow struct {
+	Name   string
+	Values []string
+}
+
+// htmlGamePage everything the game page template needs
+type htmlGamePage struct {
+	Title      string
+	Season     string
+	Info       []string
+	Scorecards []htmlScorecard
+	Innings    []int
+	Linescore  []htmlLinescoreRow
+	Teams      []string
+	BoxScore   BoxScore
+	Plays      []htmlPlay
+}
+
+// htmlSeasonPage everything the season index template needs
+type htmlSeasonPage struct {
+	Season string
+	Games  []htmlGameLink
+}
+
+// htmlGameLink an entry in the season index
+type htmlGameLink struct {
+	File   string
+	Result string
+}
+
+// htmlStyle is inlined into every page so the viewer works offline
+const htmlStyle = `
+body { font-family: Helvetica, Arial, sans-serif; margin: 20px; color: #222; }
+h1 { font-size: 22px; margin-bottom: 4px; }
+h2 { font-size: 18px; margin: 24px 0 6px; }
+p.info { margin: 2px 0; font-size: 13px; }
+table { border-collapse: collapse; margin-bottom: 8px; }
+th, td { border: 1px solid #999; padding: 2px 6px; font-size: 13px; }
+td.num, th.num { text-align: right; }
+table.grid td.box { padding: 0; width: 72px; }
+table.grid td.name { width: 210px; vertical-align: top; }
+td.box button { border: 0; background: none; padding: 0; cursor: pointer; display: block; }
+td.box button:hover, td.box button.selected { background: #fff3bf; }
+#detail { position: fixed; top: 20px; right: 20px; width: 320px; background: #fff; border: 1px solid #666; padding: 10px 14px; font-size: 13px; box-shadow: 2px 2px 6px #aaa; display: none; }
+#detail h3 { margin: 0 0 6px; font-size: 15px; }
+#detail ol { margin: 4px 0; padding-left: 20px; }
+#detail .close { float: right; cursor: pointer; }
+`
+
+// htmlScript shows the details of a play when its box is clicked
+const htmlScript = `
+var details = document.getElementById('detail');
+var selected = null;
+function show(index) {
+  var play = plays[index];
+  var box = document.getElementById('play-' + index);
+  if (selected) { selected.classList.remove('selected'); }
+  selected = box;
+  box.classList.add('selected');
+  document.getElementById('detail-heading').textContent = play.heading;
+  document.getElementById('detail-description').textContent = play.description + ' (' + play.source + ')';
+  document.getElementById('detail-count').textContent = play.count;
+  var list = document.getElementById('detail-pitches');
+  list.innerHTML = '';
+  play.pitches.forEach(function (pitch) {
+    var item = document.createElement('li');
+    item.textContent = pitch;
+    list.appendChild(item);
+  });
+  document.getElementById('detail-none').style.display = play.pitches.length ? 'none' : 'block';
+  document.getElementById('detail-before').textContent = play.before;
+  document.getElementById('detail-after').textContent = play.after;
+  document.getElementById('detail-values').textContent = play.values;
+  details.style.display = 'block';
+}
+function hide() {
+  details.style.display = 'none';
+  if (selected) { selected.classList.remove('selected'); selected = null; }
+}
+document.addEventListener('keydown', function (e) { if (e.key === 'Escape') { hide(); } });
+`
+
+// htmlBattingColumns and htmlPitchingColumns head the box score tables, each translated on its own
+var (
+	htmlBattingColumns  = []string{"AB", "R", "H", "RBI", "BB", "SO"}
+	htmlPitchingColumns = []string{"IP", "H", "R", "ER", "BB", "SO"}
+)
+
+// htmlGameTemplate lays out a game page
+var htmlGameTemplate = template.Must(template.New("game").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>{{.Style}}</style>
+</head>
+<body>
+<p><a href="{{.Page.Season}}.html">{{$.Catalog.Sprintf "%s season" .Page.Season}}</a></p>
+<h1>{{.Page.Title}}</h1>
+{{range .Page.Info}}<p class="info">{{.}}</p>
+{{end}}
+{{range .Page.Scorecards}}
+<h2>{{.Name}}</h2>
+<table class="grid">
+<tr><th>{{$.Catalog.Text "Batter"}}</th>{{range .Columns}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>
+<td class="name" style="height: {{.Height}}px"><b>{{.Slot}}</b> {{range $i, $name := .Players}}{{if $i}}<br>{{end}}{{$name}}{{end}}</td>
+{{range .Cells}}<td class="box">{{if ge .Play 0}}<button id="play-{{.Play}}" onclick="show({{.Play}})">{{.Diagram}}</button>{{end}}</td>
+{{end}}</tr>
+{{end}}<tr><th>{{$.Catalog.Text "R / H"}}</th>{{range .Totals}}<td class="num">{{.}}</td>{{end}}</tr>
+</table>
+<p class="info">{{.Pitchers}}</p>
+{{end}}
+<h2>{{$.Catalog.Text "Linescore"}}</h2>
+<table>
+<tr><th></th>{{range .Page.Innings}}<th class="num">{{.}}</th>{{end}}<th class="num">{{$.Catalog.Text "R"}}</th><th class="num">{{$.Catalog.Text "H"}}</th><th class="num">{{$.Catalog.Text "E"}}</th></tr>
+{{range .Page.Linescore}}<tr><th>{{.Name}}</th>{{range .Values}}<td class="num">{{.}}</td>{{end}}</tr>
+{{end}}</table>
+<h2>{{$.Catalog.Text "Box score"}}</h2>
+{{range $team, $name := .Page.Teams}}
+<table>
+<tr><th>{{$name}}</th>{{range $.BattingColumns}}<th class="num">{{$.Catalog.Text .}}</th>{{end}}</tr>
+{{range index $.Page.BoxScore.Batting $team}}<tr><td>{{if .Substitute}}&nbsp;&nbsp;{{end}}{{.Name}} {{.Position}}</td><td class="num">{{.AB}}</td><td class="num">{{.R}}</td><td class="num">{{.H}}</td><td class="num">{{.RBI}}</td><td class="num">{{.BB}}</td><td class="num">{{.SO}}</td></tr>
+{{end}}</table>
+<table>
+<tr><th>{{$.Catalog.Text "Pitching"}}</th>{{range $.PitchingColumns}}<th class="num">{{$.Catalog.Text .}}</th>{{end}}</tr>
+{{range index $.Page.BoxScore.Pitching $team}}<tr><td>{{.Name}}</td><td class="num">{{.InningsPitched}}</td><td class="num">{{.H}}</td><td class="num">{{.R}}</td><td class="num">{{.ER}}</td><td class="num">{{.BB}}</td><td class="num">{{.SO}}</td></tr>
+{{end}}</table>
+{{end}}
+<div id="detail">
+<span class="close" onclick="hide()">&#10005;</span>
+<h3 id="detail-heading"></h3>
+<p id="detail-description"></p>
+<p id="detail-count"></p>
+<ol id="detail-pitches"></ol>
+<p id="detail-none">{{$.Catalog.Text "No pitch sequence recorded"}}</p>
+<p id="detail-before"></p>
+<p id="detail-after"></p>
+<p id="detail-values"></p>
+</div>
+<script>
+var plays = {{.Page.Plays}};
+{{.Script}}
+</script>
+</body>
+</html>
+`))
+
+// htmlSeasonTemplate lays out the index of a season's games
+var htmlSeasonTemplate = template.Must(template.New("season").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{$.Catalog.Sprintf "%s season" .Page.Season}}</title>
+<style>{{.Style}}</style>
+</head>
+<body>
+<h1>{{$.Catalog.Sprintf "%s season" .Page.Season}}</h1>
+<ol>
+{{range .Page.Games}}<li><a href="{{.File}}">{{.Result}}</a></li>
+{{end}}</ol>
+</body>
+</html>
+`))
+
+// describePitches spells out a pitch sequence one pitch per entry, folding the markers into the pitch they describe
+func describePitches(pitches string) []string {
+	described := make([]string, 0)
+	notes := make([]string, 0)
+	byCatcher := false
+
+	for _, r := range pitches {
+		code := string(r)
+		switch code {
+		case "*", ">":
+			notes = append(notes, PitchCodes[code])
+			continue
+		case "+":
+			byCatcher = true
+			continue
+		case ".":
+			continue
+		}
+
+		description, ok := PitchCodes[code]
+		if !ok {
+			description = code
+		}
+		if byCatcher {
+			description = fmt.Sprintf("%s by the catcher", description)
+			byCatcher = false
+		}
+		if len(notes) > 0 {
+			description = fmt.Sprintf("%s (%s)", description, strings.Join(notes, ", "))
+			notes = notes[:0]
+		}
+		described = append(described, description)
+	}
+
+	return described
+}
+
+// describeSituation spells out the outs and who is on base
+func describeSituation(situation Situation, names map[string]string, catalog Catalog) string {
+	outs := catalog.Sprintf("%d outs", situation.Outs)
+	if situation.Outs == 1 {
+		outs = catalog.Text("1 out")
+	}
+
+	runners := make([]string, 0)
+	for base, id := range situation.Bases {
+		if id != "" {
+			runners = append(runners, catalog.Sprintf("%s on %s", names[id], catalog.Base(fmt.Sprintf("%d", base+1))))
+		}
+	}
+	if len(runners) == 0 {
+		runners = append(runners, catalog.Text("bases empty"))
+	}
+
+	return catalog.Sprintf("%s, %s, score %d-%d", outs, strings.Join(runners, ", "), situation.Score[0], situation.Score[1])
+}
+
+// playerNames maps the id of everyone who appeared in a game to their name
+func playerNames(game Game) map[string]string {
+	names := make(map[string]string)
+	for _, player := range append(append([]Player{}, game.Lineup.Visitor...), game.Lineup.Home...) {
+		names[player.ID] = player.Name
+	}
+	for _, play := range game.Plays {
+		for _, sub := range play.Substitutions {
+			names[sub.Player.ID] = sub.Player.Name
+		}
+	}
+
+	return names
+}
+
+// createHTMLPlays the details of every play in a game for the viewer
+func createHTMLPlays(game Game, catalog Catalog) []htmlPlay {
+	names := playerNames(game)
+	plays := make([]htmlPlay, 0)
+
+	for _, play := range game.Plays {
+		half := catalog.Text([]string{"Top", "Bottom"}[play.Team])
+		count := catalog.Text("Count: not recorded")
+		if play.Count != "" {
+			count = catalog.Sprintf("Count: %s", play.Count)
+		}
+
+		plays = append(plays, htmlPlay{
+			Heading:     catalog.Sprintf("%s %s: %s facing %s", half, ordinal(play.Inning), names[play.BatterID], names[play.PitcherID]),
+			Description: play.Description,
+			Source:      play.Event.Source,
+			Count:       count,
+			Pitches:     describePitches(play.Pitches),
+			Before:      catalog.Sprintf("Before: %s", describeSituation(play.Before, names, catalog)),
+			After:       catalog.Sprintf("After: %s", describeSituation(play.After, names, catalog)),
+			Values:      catalog.Sprintf("Runs: %d   RE24: %+.2f   WPA: %+.3f", play.Runs, play.RE24, play.WPA),
+		})
+	}
+
+	return plays
+}
+
+// createHTMLScorecard lays out a team's scorecard grid, drawing each at-bat as a small svg
+func createHTMLScorecard(scorecard Scorecard, catalog Catalog) htmlScorecard {
+	page := htmlScorecard{Name: scorecard.Name, Columns: scorecard.Columns}
+
+	for slot, players := range scorecard.Slots {
+		height := slotHeight(players)
+		row := htmlRow{Slot: slot + 1, Height: height, Cells: make([]htmlCell, len(scorecard.Columns))}
+		for _, entry := range players.Players {
+			row.Players = append(row.Players, describeEntry(entry))
+		}
+		for i := range row.Cells {
+			row.Cells[i].Play = -1
+		}
+		for _, box := range players.Boxes {
+			c := &svgCanvas{}
+			fmt.Fprintf(&c.b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%g">`, drawCellWidth, height)
+			drawBox(c, 0, 0, height, box, catalog)
+			c.b.WriteString("</svg>")
+			// the svg canvas escapes all of its text, so the drawing is safe to put in the page as is
+			row.Cells[box.Column] = htmlCell{Play: box.Play, Diagram: template.HTML(c.b.String())}
+		}
+		page.Rows = append(page.Rows, row)
+	}
+
+	for i, inning := range scorecard.Columns {
+		cell := ""
+		if i == 0 || scorecard.Columns[i-1] != inning {
+			for _, totals := range scorecard.Innings {
+				if totals.Inning == inning {
+					cell = fmt.Sprintf("%d / %d", totals.Runs, totals.Hits)
+				}
+			}
+		}
+		page.Totals = append(page.Totals, cell)
+	}
+
+	pitchers := make([]string, 0)
+	for _, pitcher := range scorecard.Pitchers {
+		pitchers = append(pitchers, describePitcher(pitcher))
+	}
+	page.Pitchers = catalog.Sprintf("Pitchers: %s", strings.Join(pitchers, ", "))
+
+	return page
+}
+
+// gameSeason the season a game was played in, taken from its id
+func gameSeason(game Game) string {
+	if len(game.ID) < 7 {
+		return "unknown"
+	}
+
+	return game.ID[3:7]
+}
+
+// Game.toHTML() Renders a game as a standalone html page with both scorecards, the linescore and box score, its labels
+// in the language of the catalog
+func (game *Game) toHTML(catalog Catalog) string {
+	box := CreateBoxScore(*game)
+	page := htmlGamePage{
+		Title:    describeResult(*game),
+		Season:   gameSeason(*game),
+		Info:     describeGameInfo(game.Info, catalog),
+		Teams:    []string{game.Info.Visteam.Name, game.Info.Hometeam.Name},
+		BoxScore: box,
+		Plays:    createHTMLPlays(*game, catalog),
+	}
+
+	for team := 0; team < 2; team++ {
+		page.Scorecards = append(page.Scorecards, createHTMLScorecard(CreateScorecard(*game, team), catalog))
+		page.Linescore = append(page.Linescore, htmlLinescoreRow{Name: page.Teams[team], Values: linescoreRow(box.Linescore, team)})
+	}
+	for inning := 1; inning <= len(box.Linescore.Innings[0]); inning++ {
+		page.Innings = append(page.Innings, inning)
+	}
+
+	b := &bytes.Buffer{}
+	err := htmlGameTemplate.Execute(b, map[string]interface{}{
+		"Title":           page.Title,
+		"Style":           template.CSS(htmlStyle),
+		"Script":          template.JS(htmlScript),
+		"Page":            page,
+		"Catalog":         catalog,
+		"BattingColumns":  htmlBattingColumns,
+		"PitchingColumns": htmlPitchingColumns,
+	})
+	if err != nil {
+		panic("Error rendering game page")
+	}
+
+	return b.String()
+}
+
+// LoadGame reads back a game written by toDisk
+func LoadGame(path string) (Game, error) {
+	game := Game{}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return game, err
+	}
+
+	err = json.Unmarshal(data, &game)
+
+	return game, err
+}
+
+// writeHTML renders a page for every game json file in the output path, along with an index page for each season, their
+// labels in the language of the catalog
+func writeHTML(path string, catalog Catalog) error {
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no game json found in %s, run without a command first to write it", path)
+	}
+
+	seasons := make(map[string][]htmlGameLink)
+	for _, file := range files {
+		game, err := LoadGame(file)
+		if err != nil {
+			return fmt.Errorf("reading %s: %v", file, err)
+		}
+
+		name := fmt.Sprintf("%s.html", game.ID)
+		err = ioutil.WriteFile(filepath.Join(path, name), []byte(game.toHTML(catalog)), 0644)
+		if err != nil {
+			return err
+		}
+
+		season := gameSeason(game)
+		seasons[season] = append(seasons[season], htmlGameLink{File: name, Result: describeResult(game)})
+	}
+
+	for season, games := range seasons {
+		sort.Slice(games, func(i, j int) bool {
+			return games[i].File < games[j].File
+		})
+
+		b := &bytes.Buffer{}
+		err := htmlSeasonTemplate.Execute(b, map[string]interface{}{
+			"Style":   template.CSS(htmlStyle),
+			"Page":    htmlSeasonPage{Season: season, Games: games},
+			"Catalog": catalog,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = ioutil.WriteFile(filepath.Join(path, fmt.Sprintf("%s.html", season)), b.Bytes(), 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,126 +1,160 @@
 package main
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
-// Info Represents all the game and administrative information tied to a game
+// Info Represents all the game and administrative information tied to a game.
+// Anything the event file marks as unknown or doesn't record is null in the json rather than a made up zero.
 type Info struct {
-	Visteam    Team   `json:"visitingTeam"`
-	Hometeam   Team   `json:"homeTeam"`
-	Site       Park   `json:"site"`
-	Date       string `json:"date"`
-	GameType   string `json:"gameType"`
-	Starttime  string `json:"startTime"`
-	Daynight   string `json:"dayNight"`
-	Usedh      bool   `json:"useDH"`
-	Umphome    Person `json:"homeUmpire"`
-	Ump1b      Person `json:"1bUmpire"`
-	Ump2b      Person `json:"2bUmpire"`
-	Ump3b      Person `json:"3bUmpire"`
-	Scorer     string `json:"scorer"`
-	Inputter   string `json:"inputter"`
-	Howscored  string `json:"howScored"`
-	Pitches    string `json:"pitches"`
-	Temp       int    `json:"temp"`
-	Winddir    string `json:"windDirection"`
-	Windspeed  int    `json:"windSpeed"`
-	Fieldcond  string `json:"fieldConditions"`
-	Precip     string `json:"precipitation"`
-	Sky        string `json:"sky"`
-	Timeofgame int    `json:"timeOfGame"`
-	Attendance int    `json:"attendance"`
-	Wp         Person `json:"winningPitcher"`
-	Lp         Person `json:"losingPitcher"`
-	Save       Person `json:"save"`
-	Gwrbi      Person `json:"gameWinningRBI"`
+	Visteam   Team       `json:"visitingTeam"`
+	Hometeam  Team       `json:"homeTeam"`
+	Site      Park       `json:"site"`
+	Date      Date       `json:"date"`
+	GameType  string     `json:"gameType"`
+	Starttime *ClockTime `json:"startTime"`
+	// Timestamp is when the game started in the park's time zone, null when the start time or time zone isn't known
+	Timestamp  *time.Time `json:"timestamp"`
+	Daynight   *string    `json:"dayNight"`
+	Usedh      bool       `json:"useDH"`
+	Umphome    Person     `json:"homeUmpire"`
+	Ump1b      Person     `json:"1bUmpire"`
+	Ump2b      Person     `json:"2bUmpire"`
+	Ump3b      Person     `json:"3bUmpire"`
+	UmpLF      Person     `json:"lfUmpire"`
+	UmpRF      Person     `json:"rfUmpire"`
+	Scorer     *string    `json:"scorer"`
+	Translator *string    `json:"translator"`
+	Inputter   *string    `json:"inputter"`
+	// Inputtime and Edittime are when the game was entered and last edited, as the event file writes them
+	Inputtime  *string        `json:"inputTime"`
+	Edittime   *string        `json:"editTime"`
+	Howscored  *string        `json:"howScored"`
+	Pitches    *string        `json:"pitches"`
+	Temp       *int           `json:"temp"`
+	Winddir    WindDirection  `json:"windDirection"`
+	Windspeed  *int           `json:"windSpeed"`
+	Fieldcond  FieldCondition `json:"fieldConditions"`
+	Precip     Precipitation  `json:"precipitation"`
+	Sky        Sky            `json:"sky"`
+	Timeofgame *int           `json:"timeOfGame"`
+	Attendance *int           `json:"attendance"`
+	Wp         Person         `json:"winningPitcher"`
+	Lp         Person         `json:"losingPitcher"`
+	Save       Person         `json:"save"`
+	Gwrbi      Person         `json:"gameWinningRBI"`
+	// Records are the info records as the event file wrote them, kept so the file can be written back out unchanged.
+	// They only repeat the fields above, so they're left out of the json.
+	Records []InfoRecord `json:"-"`
+}
+
+// InfoRecord an info record as it appeared in the event file
+type InfoRecord struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	// Quoted is whether the value was wrapped in quotes
+	Quoted bool `json:"quoted,omitempty"`
 }
 
 // CreateInfo Given a slice of raw comma-delimited strings, return an Info struct
 func CreateInfo(source []string) Info {
 	info := Info{}
 	infoRecords := GetRecords(source)
+	// teams can only be looked up once the date of the game is known
+	visteam := ""
+	hometeam := ""
+
+	for i, r := range infoRecords {
+		info.Records = append(info.Records, InfoRecord{
+			Key:    r[1],
+			Value:  r[2],
+			Quoted: strings.HasPrefix(strings.SplitN(source[i], ",", 3)[2], `"`),
+		})
 
-	for _, r := range infoRecords {
 		switch r[1] {
 		case "visteam":
-			info.Visteam = Teams[r[2]]
+			visteam = r[2]
 		case "hometeam":
-			info.Hometeam = Teams[r[2]]
+			hometeam = r[2]
 		case "site":
-			info.Site = Parks[r[2]]
+			info.Site = LookupPark(r[2])
 		case "date":
-			info.Date = r[2]
+			info.Date = ParseDate(r[2])
 		case "number":
 			{
 				gameType, _ := strconv.Atoi(r[2])
 				info.GameType = GameTypes[gameType]
 			}
 		case "starttime":
-			info.Starttime = r[2]
+			info.Starttime = ParseStartTime(r[2])
 		case "daynight":
-			info.Daynight = strings.Title(r[2])
+			if daynight := recorded(r[2]); daynight != nil {
+				title := strings.Title(*daynight)
+				info.Daynight = &title
+			}
 		case "usedh":
 			useDh, _ := strconv.ParseBool(r[2])
 			info.Usedh = useDh
 		case "umphome":
-			info.Umphome = People[r[2]]
+			info.Umphome = LookupPerson(r[2])
 		case "ump1b":
-			info.Ump1b = People[r[2]]
+			info.Ump1b = LookupPerson(r[2])
 		case "ump2b":
-			info.Ump2b = People[r[2]]
+			info.Ump2b = LookupPerson(r[2])
 		case "ump3b":
-			info.Ump3b = People[r[2]]
+			info.Ump3b = LookupPerson(r[2])
+		case "umplf":
+			info.UmpLF = LookupPerson(r[2])
+		case "umprf":
+			info.UmpRF = LookupPerson(r[2])
 		case "scorer":
-			info.Scorer = r[2]
+			info.Scorer = recorded(r[2])
+		case "translator":
+			info.Translator = recorded(r[2])
 		case "inputter":
-			info.Inputter = r[2]
+			info.Inputter = recorded(r[2])
+		case "inputtime":
+			info.Inputtime = recorded(r[2])
+		case "edittime":
+			info.Edittime = recorded(r[2])
 		case "howscored":
-			info.Howscored = r[2]
+			info.Howscored = recorded(r[2])
 		case "pitches":
-			info.Pitches = r[2]
-		case "temp":
-			{
-				temp, _ := strconv.Atoi(r[2])
-				info.Temp = temp
-			}
+			info.Pitches = recorded(r[2])
+		case "temp": // 0 when not recorded
+			info.Temp = recordedNumber(r[2], 0)
 		case "winddir":
-			info.Winddir = WindDirections[r[2]]
-		case "windspeed":
-			{
-				speed, _ := strconv.Atoi(r[2])
-				info.Windspeed = speed
-			}
-		case "fieldcond": // dry, soaked, wet, unknown
-			info.Fieldcond = strings.Title(r[2])
+			info.Winddir = ParseWindDirection(r[2])
+		case "windspeed": // -1 when not recorded
+			info.Windspeed = recordedNumber(r[2], -1)
+		case "fieldcond": // damp, dry, soaked, wet, unknown
+			info.Fieldcond = ParseFieldCondition(r[2])
 		case "precip": // drizzle, none, rain, showers, snow, unknown
-			info.Precip = strings.Title(r[2])
+			info.Precip = ParsePrecipitation(r[2])
 		case "sky": // cloudy, dome, night, overcast, sunny, unknown
-			info.Sky = strings.Title(r[2])
-		case "timeofgame":
-			{
-				time, _ := strconv.Atoi(r[2])
-				info.Timeofgame = time
-			}
-		case "attendance":
-			{
-				attendance, _ := strconv.Atoi(r[2])
-				info.Attendance = attendance
-			}
+			info.Sky = ParseSky(r[2])
+		case "timeofgame": // 0 when not recorded
+			info.Timeofgame = recordedNumber(r[2], 0)
+		case "attendance": // 0 when not recorded
+			info.Attendance = recordedNumber(r[2], 0)
 		case "wp":
-			info.Wp = People[r[2]]
+			info.Wp = LookupPerson(r[2])
 		case "lp":
-			info.Lp = People[r[2]]
+			info.Lp = LookupPerson(r[2])
 		case "save":
-			info.Save = People[r[2]]
+			info.Save = LookupPerson(r[2])
 		case "gwrbi":
-			info.Gwrbi = People[r[2]]
+			info.Gwrbi = LookupPerson(r[2])
 		default:
-			panic(fmt.Sprintf("The field '%s' does not have a handler", r[1]))
+			// keys from other seasons and releases, such as oscorer, htbf or tiebreaker, are kept in Records only
 		}
 	}
 
+	info.Visteam = LookupTeam(visteam, info.Date)
+	info.Hometeam = LookupTeam(hometeam, info.Date)
+	info.Timestamp = gameTimestamp(info.Date, info.Starttime, info.Site)
+
 	return info
 }
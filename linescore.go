@@ -0,0 +1,42 @@
+package main
+
+// Linescore the runs each team scored by inning along with their totals for the game
+type Linescore struct {
+	// Innings holds the runs for every half inning a team came to bat
+	Innings    [2][]int `json:"innings"`
+	Runs       [2]int   `json:"runs"`
+	Hits       [2]int   `json:"hits"`
+	Errors     [2]int   `json:"errors"`
+	LeftOnBase [2]int   `json:"leftOnBase"`
+}
+
+// CreateLinescore Given the plays of a game, total up its linescore
+func CreateLinescore(plays []Play) Linescore {
+	linescore := Linescore{
+		Innings: [2][]int{make([]int, 0), make([]int, 0)},
+	}
+
+	for _, half := range halfInnings(plays) {
+		team := half[0].Team
+		runs := 0
+
+		for _, play := range half {
+			runs += play.Runs
+			if play.Event.HitValue() > 0 {
+				linescore.Hits[team]++
+			}
+			linescore.Errors[1-team] += len(play.Event.Errors)
+		}
+
+		for _, runner := range half[len(half)-1].After.Bases {
+			if runner != "" {
+				linescore.LeftOnBase[team]++
+			}
+		}
+
+		linescore.Innings[team] = append(linescore.Innings[team], runs)
+		linescore.Runs[team] += runs
+	}
+
+	return linescore
+}
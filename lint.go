@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LintIssue a problem found in a record of an event file
+type LintIssue struct {
+	File    string `json:"file"`
+	GameID  string `json:"gameId"`
+	Line    int    `json:"line"`
+	Record  string `json:"record"`
+	Message string `json:"message"`
+}
+
+// String formats the issue the way compilers do, file:line: followed by the game and the problem
+func (issue LintIssue) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s\n\t%s", issue.File, issue.Line, issue.GameID, issue.Message, issue.Record)
+}
+
+// lintLine a record of an event file along with the line it was on
+type lintLine struct {
+	number int
+	text   string
+	fields []string
+}
+
+// lintRecordFields how many fields each record type needs
+var lintRecordFields = map[string]int{
+	"id":      2,
+	"version": 2,
+	"info":    3,
+	"start":   6,
+	"sub":     6,
+	"play":    7,
+	"com":     2,
+	"data":    4,
+}
+
+// parseLintRecord splits a record into its fields, reporting malformed quoting
+func parseLintRecord(text string) ([]string, error) {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+
+	return reader.Read()
+}
+
+// pitchCount Given the pitches of a plate appearance, return the balls and strikes they add up to
+func pitchCount(pitches string) (int, int) {
+	balls, strikes := 0, 0
+
+	for _, r := range pitches {
+		switch r {
+		case 'B', 'I', 'P', 'V':
+			if balls < 3 {
+				balls++
+			}
+		case 'C', 'K', 'M', 'Q', 'S', 'T', 'L', 'O':
+			if strikes < 2 {
+				strikes++
+			}
+		case 'F', 'R':
+			// fouls only count as strikes until there are two
+			if strikes < 2 {
+				strikes++
+			}
+		}
+	}
+
+	return balls, strikes
+}
+
+// lintCount checks that the count recorded for a play matches its pitch sequence, the count being before the last pitch
+func lintCount(count string, pitches string) error {
+	if len(count) != 2 || strings.Contains(count, "?") || pitches == "" {
+		return nil
+	}
+
+	// the last pitch ends the plate appearance, so it isn't part of the count
+	pitched := strings.TrimRight(pitches, ".123>+*N")
+	if len(pitched) > 0 {
+		pitched = pitched[:len(pitched)-1]
+	}
+	balls, strikes := pitchCount(pitched)
+
+	if count != fmt.Sprintf("%d%d", balls, strikes) {
+		return fmt.Errorf("count %s doesn't match the pitches %s, which come to %d-%d", count, pitches, balls, strikes)
+	}
+
+	return nil
+}
+
+// lintGame replays the records of one game, reporting anything that doesn't parse or doesn't fit the state of the game
+func lintGame(file string, lines []lintLine) []LintIssue {
+	issues := make([]LintIssue, 0)
+	gameID := lines[0].fields[1]
+	report := func(line lintLine, message string) {
+		issues = append(issues, LintIssue{File: file, GameID: gameID, Line: line.number, Record: line.text, Message: message})
+	}
+
+	usedh := false
+	starts := make([][]string, 0)
+	var state *GameState
+	up := [2]int{1, 1}
+	// last is the latest play record, unparsed whether a play of the current half inning didn't parse
+	last := lintLine{}
+	unparsed := false
+
+	for _, line := range lines {
+		switch line.fields[0] {
+		case "info":
+			if line.fields[1] == "usedh" {
+				usedh, _ = strconv.ParseBool(line.fields[2])
+			}
+		case "start":
+			starts = append(starts, line.fields)
+		case "sub", "play":
+			if state == nil {
+				lineupState := NewGameState(CreateLineup(starts, usedh))
+				state = &lineupState
+			}
+		}
+
+		switch line.fields[0] {
+		case "sub":
+			if _, ok := FieldingPositions[line.fields[5]]; !ok {
+				report(line, fmt.Sprintf("unknown fielding position '%s'", line.fields[5]))
+			}
+			state.Substitute(createSubstitution(line.fields))
+		case "play":
+			inning, err := strconv.Atoi(line.fields[1])
+			team, teamErr := strconv.Atoi(line.fields[2])
+			if err != nil || teamErr != nil || (team != 0 && team != 1) {
+				report(line, "inning or team isn't a number")
+				continue
+			}
+
+			batter := line.fields[3]
+			slot := 0
+			for s := 1; s <= 9; s++ {
+				if state.Lineups[team][s] == batter {
+					slot = s
+				}
+			}
+
+			// a half inning that's over is reported against its last play, unless one of its plays couldn't be read and
+			// so its outs can't be counted
+			if inning != state.Inning || team != state.Half {
+				if err := state.Enter(inning, team); err != nil && !unparsed {
+					report(last, err.Error())
+				}
+				unparsed = false
+			}
+			last = line
+
+			event, err := ParseEvent(line.fields[6])
+			if err != nil {
+				report(line, err.Error())
+				// assume the batter had their turn so the batters after them aren't reported too
+				if slot > 0 {
+					up[team] = slot%9 + 1
+				}
+				unparsed = true
+				continue
+			}
+
+			switch {
+			case slot == 0:
+				report(line, fmt.Sprintf("%s isn't in the batting order", batter))
+			case slot != up[team]:
+				report(line, fmt.Sprintf("batting out of order, %s in slot %d is up but %s in slot %d batted", state.Lineups[team][up[team]], up[team], batter, slot))
+			}
+
+			if event.IsPlateAppearance() {
+				if err := lintCount(line.fields[4], line.fields[5]); err != nil {
+					report(line, err.Error())
+				}
+				if slot > 0 {
+					up[team] = slot%9 + 1
+				}
+			}
+
+			_, _, _, problems := state.Apply(inning, team, batter, event)
+			for _, problem := range problems {
+				report(line, problem.Error())
+			}
+		}
+	}
+
+	return issues
+}
+
+// LintEventFile Given the name and contents of an event file, check every record and return the issues found
+func LintEventFile(file string, data []byte) []LintIssue {
+	issues := make([]LintIssue, 0)
+	games := make([][]lintLine, 0)
+	gameID := ""
+	report := func(line lintLine, message string) {
+		issues = append(issues, LintIssue{File: file, GameID: gameID, Line: line.number, Record: line.text, Message: message})
+	}
+
+	for i, text := range strings.Split(string(data), "\n") {
+		text = strings.TrimRight(text, "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		line := lintLine{number: i + 1, text: text}
+		fields, err := parseLintRecord(text)
+		if err != nil {
+			report(line, err.Error())
+			continue
+		}
+		line.fields = fields
+
+		need, ok := lintRecordFields[fields[0]]
+		switch {
+		case !ok && !Contains([]string{"badj", "padj", "ladj", "radj", "presadj"}, fields[0]):
+			report(line, fmt.Sprintf("unknown record type '%s'", fields[0]))
+			continue
+		case len(fields) < need:
+			report(line, fmt.Sprintf("%s record needs %d fields but has %d", fields[0], need, len(fields)))
+			continue
+		}
+
+		if fields[0] == "id" {
+			gameID = fields[1]
+			games = append(games, []lintLine{line})
+		} else if len(games) == 0 {
+			report(line, "record comes before the first id record")
+		} else {
+			games[len(games)-1] = append(games[len(games)-1], line)
+		}
+	}
+
+	for _, lines := range games {
+		issues = append(issues, lintGame(file, lines)...)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+
+	return issues
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintEventFile(t *testing.T) {
+	// issues are given by the index of the play they're reported against
+	type issue struct {
+		play    int
+		message string
+	}
+	tests := []struct {
+		name   string
+		plays  []string
+		issues []issue
+	}{
+		{
+			"clean",
+			[]string{
+				"play,1,0,colev001,10,BX,S7",
+				"play,1,0,mcgew001,11,BC,SB2",
+				"play,1,0,mcgew001,32,BC.BBFFS,K",
+				"play,1,0,herrt001,00,X,63/G6",
+				"play,1,0,clarj001,01,CX,8/F8.2-H",
+				"play,1,1,dyksl001,??,,K",
+				"play,1,1,teuft001,??,,63",
+				"play,1,1,hernk001,??,,8",
+			},
+			nil,
+		},
+		{
+			"out of order",
+			[]string{"play,1,0,mcgew001,??,,8"},
+			[]issue{{0, "batting out of order, colev001 in slot 1 is up but mcgew001 in slot 2 batted"}},
+		},
+		{
+			"count",
+			[]string{"play,1,0,colev001,21,BX,S7"},
+			[]issue{{0, "count 21 doesn't match the pitches BX, which come to 1-0"}},
+		},
+		{
+			"four outs",
+			[]string{
+				"play,1,0,colev001,??,,K",
+				"play,1,0,mcgew001,??,,K",
+				"play,1,0,herrt001,??,,K",
+				"play,1,0,clarj001,??,,K",
+			},
+			[]issue{{3, "4 outs in the inning"}},
+		},
+		{
+			"two outs",
+			[]string{
+				"play,1,0,colev001,??,,K",
+				"play,1,0,mcgew001,??,,K",
+				"play,1,1,dyksl001,??,,K",
+			},
+			[]issue{{1, "half inning ended with 2 outs"}},
+		},
+	}
+
+	for _, test := range tests {
+		file := gameWithPlays(t, test.plays...)
+		// the plays are the last records, before the final line break
+		first := len(strings.Split(file, "\r\n")) - len(test.plays)
+
+		issues := LintEventFile("test.EVN", []byte(file))
+		if len(issues) != len(test.issues) {
+			t.Errorf("%s: want %d issues, got %v", test.name, len(test.issues), issues)
+			continue
+		}
+		for i, want := range test.issues {
+			if issues[i].Line != first+want.play || issues[i].Message != want.message {
+				t.Errorf("%s: want line %d %q, got line %d %q", test.name, first+want.play, want.message, issues[i].Line, issues[i].Message)
+			}
+		}
+	}
+}
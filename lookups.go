@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LookupPark Given a park id, return the park from the reference data, or a park named by its id when it isn't known
+func LookupPark(id string) Park {
+	if park, ok := Parks[id]; ok || id == "" {
+		return park
+	}
+
+	return Park{ID: id, Name: id}
+}
+
+// LookupPerson Given a person id, return the person from the reference data, or a person carrying just the id when they aren't known.
+// Event files mark an empty umpiring spot with (none), which gives an empty person.
+func LookupPerson(id string) Person {
+	if person, ok := People[id]; ok || id == "" || id == "(none)" {
+		return person
+	}
+
+	return Person{ID: id}
+}
+
+// ReferenceReport the ids missing from the reference data, by kind (team, park, umpire or player) then id, with the games they turned up in
+type ReferenceReport map[string]map[string][]string
+
+// UnresolvedReferences collects every id missing from the reference data over a run
+var UnresolvedReferences = make(ReferenceReport)
+
+// add records an id that couldn't be found, blank ids mean nothing was recorded and aren't missing
+func (report ReferenceReport) add(kind string, id string, gameID string) {
+	if id == "" {
+		return
+	}
+	if _, ok := report[kind]; !ok {
+		report[kind] = make(map[string][]string)
+	}
+
+	report[kind][id] = AppendUnique(report[kind][id], gameID)
+}
+
+// checkReferences records the teams, park, umpires and players of a game that aren't in the reference data
+func (report ReferenceReport) checkReferences(game Game) {
+	for _, code := range []string{game.Info.Visteam.ID, game.Info.Hometeam.ID} {
+		if _, ok := findTeam(code, game.Info.Date); !ok {
+			report.add("team", code, game.ID)
+		}
+	}
+
+	if _, ok := Parks[game.Info.Site.ID]; !ok {
+		report.add("park", game.Info.Site.ID, game.ID)
+	}
+
+	for _, ump := range []Person{game.Info.Umphome, game.Info.Ump1b, game.Info.Ump2b, game.Info.Ump3b} {
+		if _, ok := People[ump.ID]; !ok {
+			report.add("umpire", ump.ID, game.ID)
+		}
+	}
+
+	players := []string{game.Info.Wp.ID, game.Info.Lp.ID, game.Info.Save.ID, game.Info.Gwrbi.ID}
+	for _, player := range append(append([]Player{}, game.Lineup.Visitor...), game.Lineup.Home...) {
+		players = append(players, player.ID)
+	}
+	for _, play := range game.Plays {
+		for _, sub := range play.Substitutions {
+			players = append(players, sub.Player.ID)
+		}
+	}
+	for _, id := range players {
+		if _, ok := People[id]; !ok {
+			report.add("player", id, game.ID)
+		}
+	}
+}
+
+// referenceReportGames how many of the games an id turned up in are listed in the report
+const referenceReportGames = 5
+
+// String lists the missing ids by kind, each with the games it turned up in
+func (report ReferenceReport) String() string {
+	lines := make([]string, 0)
+
+	for _, kind := range []string{"team", "park", "umpire", "player"} {
+		ids := make([]string, 0)
+		for id := range report[kind] {
+			ids = append(ids, id)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		sort.Strings(ids)
+
+		lines = append(lines, fmt.Sprintf("Unknown %s ids: %d", kind, len(ids)))
+		for _, id := range ids {
+			games := report[kind][id]
+			more := ""
+			if len(games) > referenceReportGames {
+				more = fmt.Sprintf(" and %d more", len(games)-referenceReportGames)
+				games = games[:referenceReportGames]
+			}
+			lines = append(lines, fmt.Sprintf("  %-10s %s%s", id, strings.Join(games, ", "), more))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// MarshalJSON writes an empty person, like a game without a save or an umpiring spot nobody filled, as null
+func (person Person) MarshalJSON() ([]byte, error) {
+	if person.ID == "" {
+		return []byte("null"), nil
+	}
+
+	// the alias drops this method so the fields are written as usual
+	type fields Person
+	return json.Marshal(fields(person))
+}
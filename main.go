@@ -1,33 +1,680 @@
+// The API routes use method and wildcard patterns, which need the Go 1.22 ServeMux however the binary is built
+//go:debug httpmuxgo121=0
+
 package main
 
 import (
 	"fmt"
 	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
 const outputPath string = "data/out"
 
+// hard-coding in 1986 Mets home games for now
+const eventPath string = "data/in/1986NYN.EVN"
+
+// localizedCommands the commands whose output --lang translates, the json output being the command left blank
+var localizedCommands = map[string]bool{"": true, "text": true, "serve": true, "score": true, "svg": true, "pdf": true, "html": true}
+
 func main() {
-	// hard-coding in 1986 Mets home games for now
-	eventData, err := ioutil.ReadFile("data/in/1986NYN.EVN")
+	// any TEAMYYYY, ballparks.csv, biofile or roster files next to the event file take over from the built in reference data
+	if err := LoadReferenceDir(filepath.Dir(eventPath)); err != nil {
+		fmt.Println("Error reading reference data", err)
+		return
+	}
+
+	// a flag on its own, like --lang=es, goes with the json output
+	command := ""
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "--") {
+		command = os.Args[1]
+	}
+
+	// only some commands write anything the catalogs translate, the rest turn --lang down rather than ignore it
+	if catalog, err := languageOption(); (err != nil || catalog.Language != "en") && !localizedCommands[command] {
+		fmt.Printf("%s doesn't support --lang, only the json output, text, serve, score, svg, pdf and html do\n", command)
+		return
+	}
+
+	// searching people only needs the reference data
+	if command == "people" {
+		searchPeople()
+		return
+	}
+
+	// linting reads the event files itself so it can report lines
+	if command == "lint" {
+		lintFiles()
+		return
+	}
+
+	// scoring a game starts from nothing rather than the event file
+	if command == "score" {
+		scoreGame()
+		return
+	}
+
+	catalog, err := languageOption()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	eventData, err := ioutil.ReadFile(eventPath)
 	if err != nil {
 		fmt.Println("Error reading event file", err)
 		return
 	}
 
+	games := CreateGames(eventData)
+	if len(UnresolvedReferences) > 0 {
+		// keep the report off stdout so it doesn't get mixed in with the json
+		fmt.Fprintln(os.Stderr, UnresolvedReferences)
+	}
+	if catalog.Language != "en" {
+		for i := range games {
+			games[i].Localize(catalog)
+		}
+	}
+
+	// run values need the whole corpus of games before any single play can be valued
+	re := BuildRunExpectancy(games)
+	re.ApplyRunValues(games)
+	we := BuildWinExpectancy(games)
+	we.ApplyWinProbabilities(games)
+
+	if err := os.MkdirAll(outputPath, 0755); err != nil {
+		fmt.Println("Error creating output directory", err)
+		return
+	}
+
+	switch command {
+	case "re24":
+		printRE24(re, games)
+	case "wpa":
+		printWPA(games)
+	case "svg":
+		for _, game := range games {
+			game.toSVGDisk(outputPath, catalog)
+		}
+	case "pdf":
+		writePDFs(games, catalog)
+	case "text":
+		printText(games)
+	case "html":
+		if err := writeHTML(outputPath, catalog); err != nil {
+			fmt.Println("Error writing html", err)
+		}
+	case "cwevent":
+		printEventCSV(games)
+	case "cwgame":
+		printGameCSV(games)
+	case "narrative":
+		printNarrative(games)
+	case "serve":
+		serveAPI(games)
+	case "simulate":
+		printSimulation(games)
+	case "whatif":
+		printWhatIf(games)
+	case "evn":
+		writeEventFileCopy(eventData, games)
+	default:
+		for _, game := range games {
+			// dump to terminal to check data as we go
+			fmt.Println(game.toJSON())
+
+			// dump to disk as well
+			game.toDisk(outputPath)
+		}
+	}
+}
+
+// CreateGames Given the contents of an event file, return all the games in it
+func CreateGames(eventData []byte) []Game {
 	// []string representing complete game data
 	// note that we start by chopping off the id at the beginning of the event file, but we'll put it back together when processing
 	gamesData := strings.Split(string(eventData[2:]), "\nid")
+	games := make([]Game, 0)
 
 	for _, gameData := range gamesData {
 		// make the first "id" record whole again
-		game := CreateGame("id" + gameData)
+		games = append(games, CreateGame("id"+gameData))
+	}
+
+	return games
+}
+
+// languageOption the catalog picked with --lang=<code> anywhere on the command line, English when there isn't one
+func languageOption() (Catalog, error) {
+	for _, arg := range os.Args[1:] {
+		if strings.HasPrefix(arg, "--lang=") {
+			return LookupCatalog(strings.TrimPrefix(arg, "--lang="))
+		}
+	}
+
+	return Catalogs["en"], nil
+}
+
+// printRE24 dumps the run expectancy matrix and the RE24 leaders to the terminal
+func printRE24(re RunExpectancy, games []Game) {
+	batters, pitchers := AggregateRE24(games)
+
+	fmt.Println(re.String())
+
+	for _, leaders := range []struct {
+		title  string
+		values []PlayerRunValue
+	}{{"Batters", batters}, {"Pitchers", pitchers}} {
+		fmt.Printf("\n%s\n", leaders.title)
+		for _, v := range leaders.values {
+			fmt.Printf("%-25s %5d %8.2f\n", v.Name, v.Plays, v.RE24)
+		}
+	}
+}
+
+// printWPA dumps the biggest swings of each game and the WPA leaders to the terminal
+func printWPA(games []Game) {
+	for _, game := range games {
+		fmt.Printf("%s %s at %s\n", game.ID, game.Info.Visteam.Name, game.Info.Hometeam.Name)
+		for _, play := range game.BiggestSwings(3) {
+			fmt.Printf("  %2d %-6s %-25s %-20s %+6.3f LI %.2f\n", play.Inning, []string{"top", "bottom"}[play.Team], LookupPerson(play.BatterID).FullName(), play.Event.Source, play.WPA, play.LeverageIndex)
+		}
+	}
+
+	batters, pitchers := AggregateWPA(games)
+
+	for _, leaders := range []struct {
+		title  string
+		values []PlayerWinValue
+	}{{"Batters", batters}, {"Pitchers", pitchers}} {
+		fmt.Printf("\n%s\n", leaders.title)
+		for _, v := range leaders.values {
+			fmt.Printf("%-25s %5d %8.3f\n", v.Name, v.Plays, v.WPA)
+		}
+	}
+}
+
+// writePDFs writes a printable scorecard for every game plus one for the whole event file, labelled in the language of
+// the catalog. The page size can be given after the command, letter by default.
+func writePDFs(games []Game, catalog Catalog) {
+	size := "letter"
+	if len(os.Args) > 2 && !strings.HasPrefix(os.Args[2], "--") {
+		size = os.Args[2]
+	}
+
+	season := strings.TrimSuffix(filepath.Base(eventPath), filepath.Ext(eventPath))
+	if err := writeScorecardPDF(fmt.Sprintf("%s/%s.pdf", outputPath, season), season, games, size, catalog); err != nil {
+		fmt.Println("Error writing scorecards", err)
+		return
+	}
+
+	for _, game := range games {
+		title := catalog.Sprintf("%s at %s", game.Info.Visteam.Name, game.Info.Hometeam.Name)
+		if err := writeScorecardPDF(fmt.Sprintf("%s/%s.pdf", outputPath, game.ID), title, []Game{game}, size, catalog); err != nil {
+			fmt.Println("Error writing scorecards", err)
+			return
+		}
+	}
+}
+
+// printText prints the scorecards, linescore and box score of the game with the id given after the command.
+// Add --color to highlight hits, outs and runs, --ascii for terminals without unicode, or --lang=<code> for another language.
+func printText(games []Game) {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: text <game id> [--color] [--ascii] [--lang=<code>]")
+		return
+	}
+
+	// the games have already been localized, this picks up the labels
+	catalog, _ := languageOption()
+	options := TextOptions{Catalog: catalog}
+	for _, arg := range os.Args[3:] {
+		switch arg {
+		case "--color":
+			options.Color = true
+		case "--ascii":
+			options.ASCII = true
+		}
+	}
+
+	for _, game := range games {
+		if game.ID == os.Args[2] {
+			fmt.Println(game.toText(options))
+			return
+		}
+	}
+
+	fmt.Println("No game found with id", os.Args[2])
+}
+
+// printEventCSV prints the plays in cwevent's csv format and writes them to disk as well.
+// Like cwevent, -f picks the fields to write (CWEventDefaultFields when not given) and -n adds a header line of field names.
+func printEventCSV(games []Game) {
+	spec := CWEventDefaultFields
+	header := false
+	for i := 2; i < len(os.Args); i++ {
+		switch os.Args[i] {
+		case "-n":
+			header = true
+		case "-f":
+			if i+1 < len(os.Args) {
+				i++
+				spec = os.Args[i]
+			}
+		}
+	}
+
+	fields, err := ParseFieldList(spec, len(cweventFields))
+	if err != nil {
+		fmt.Println("Usage: cwevent [-f <fields, e.g. 0-6,8>] [-n]:", err)
+		return
+	}
+
+	fmt.Print(CreateEventCSV(games, fields, header))
+
+	season := strings.TrimSuffix(filepath.Base(eventPath), filepath.Ext(eventPath))
+	if err := writeEventCSV(fmt.Sprintf("%s/%s.cwevent.csv", outputPath, season), games, fields, header); err != nil {
+		fmt.Println("Error writing event csv", err)
+	}
+}
+
+// printGameCSV prints one line per game in cwgame's csv format and writes it to disk as well.
+// Like cwgame, -f picks the standard fields (CWGameDefaultFields when not given), -x adds extended fields such as the linescores
+// and -n adds a header line of field names.
+func printGameCSV(games []Game) {
+	spec := CWGameDefaultFields
+	extendedSpec := ""
+	header := false
+	for i := 2; i < len(os.Args); i++ {
+		switch os.Args[i] {
+		case "-n":
+			header = true
+		case "-f", "-x":
+			if i+1 < len(os.Args) {
+				if os.Args[i] == "-f" {
+					spec = os.Args[i+1]
+				} else {
+					extendedSpec = os.Args[i+1]
+				}
+				i++
+			}
+		}
+	}
+
+	fields, err := ParseFieldList(spec, len(cwgameFields))
+	extended := []int{}
+	if err == nil && extendedSpec != "" {
+		extended, err = ParseFieldList(extendedSpec, len(cwgameExtendedFields))
+	}
+	if err != nil {
+		fmt.Println("Usage: cwgame [-f <fields, e.g. 0-83>] [-x <extended fields, e.g. 0-11>] [-n]:", err)
+		return
+	}
+
+	fmt.Print(CreateGameCSV(games, fields, extended, header))
 
-		// dump to terminal to check data as we go
-		fmt.Println(game.toJSON())
+	season := strings.TrimSuffix(filepath.Base(eventPath), filepath.Ext(eventPath))
+	if err := writeGameCSV(fmt.Sprintf("%s/%s.cwgame.csv", outputPath, season), games, fields, extended, header); err != nil {
+		fmt.Println("Error writing game csv", err)
+	}
+}
+
+// serveAPI serves the games as a JSON API on the address given after the command, :8080 when there isn't one
+func serveAPI(games []Game) {
+	addr := ":8080"
+	if len(os.Args) > 2 && !strings.HasPrefix(os.Args[2], "--") {
+		addr = os.Args[2]
+	}
+
+	fmt.Printf("Serving %d games on %s\n", len(games), addr)
+	if err := http.ListenAndServe(addr, CreateAPIServer(games, nil).Handler()); err != nil {
+		fmt.Println("Error serving api", err)
+	}
+}
+
+// printSimulation simulates games between two teams, given as team codes or as the id of a game whose lineups to use,
+// and prints how often each one won. -n sets how many games to play and --seed makes a run repeatable. The simulated
+// games are written to disk as an event file.
+func printSimulation(games []Game) {
+	const usage = "Usage: simulate <visitor> <home> | simulate <game id> [-n games] [--seed number]"
+	count, seed := 1000, time.Now().UnixNano()
+	teams := make([]string, 0)
+	for i := 2; i < len(os.Args); i++ {
+		switch {
+		case os.Args[i] == "-n" && i+1 < len(os.Args):
+			i++
+			if _, err := fmt.Sscanf(os.Args[i], "%d", &count); err != nil {
+				fmt.Println(usage)
+				return
+			}
+		case os.Args[i] == "--seed" && i+1 < len(os.Args):
+			i++
+			if _, err := fmt.Sscanf(os.Args[i], "%d", &seed); err != nil {
+				fmt.Println(usage)
+				return
+			}
+		case !strings.HasPrefix(os.Args[i], "--"):
+			teams = append(teams, os.Args[i])
+		}
+	}
+
+	model := BuildSimulationModel(games)
+	sides := [2]SimulationTeam{}
+	switch len(teams) {
+	case 1:
+		found := false
+		for _, game := range games {
+			if game.ID == teams[0] {
+				for team, lineup := range [][]Player{game.Lineup.Visitor, game.Lineup.Home} {
+					sides[team], _ = model.Team([]string{game.Info.Visteam.ID, game.Info.Hometeam.ID}[team])
+					sides[team].Lineup = lineup
+				}
+				found = true
+			}
+		}
+		if !found {
+			fmt.Println("No game found with id", teams[0])
+			return
+		}
+	case 2:
+		for team, code := range teams {
+			side, err := model.Team(strings.ToUpper(code))
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			sides[team] = side
+		}
+	default:
+		fmt.Println(usage)
+		return
+	}
+	if count < 1 {
+		fmt.Println("-n must be at least 1")
+		return
+	}
+
+	result, simulated := model.Project(sides[0], sides[1], count, rand.New(rand.NewSource(seed)))
+	fmt.Printf("%d games, seed %d\n", result.Games, seed)
+	for team, side := range sides {
+		fmt.Printf("%-4s %5d wins  %5.3f  %5.2f runs a game\n", side.Team, result.Wins[team], result.WinPercentage(team), result.AverageRuns(team))
+	}
+	if result.Ties > 0 {
+		fmt.Printf("%d called tied after %d innings\n", result.Ties, simMaxInnings)
+	}
+
+	path := fmt.Sprintf("%s/%sat%s.sim.EVN", outputPath, sides[0].Team, sides[1].Team)
+	if err := writeEventFile(path, simulated); err != nil {
+		fmt.Println("Error writing simulated games", err)
+		return
+	}
+	fmt.Println("Simulated games written to", path)
+}
+
+// printWhatIf changes one play of a game and plays the rest of it again, following the record for as long as it still
+// fits and simulating from there, or simulating everything after the change with --simulate. --pitch changes the play
+// partway through its pitches, -n plays the rest out that many times and --seed makes a run repeatable. Given only a
+// game id, it lists the plays by number.
+func printWhatIf(games []Game) {
+	const usage = "Usage: whatif <game id> [<play> <event>] [--pitch n] [--simulate] [-n games] [--seed number]"
+	count, seed, follow := 1, time.Now().UnixNano(), true
+	position := ReplayPosition{}
+	args := make([]string, 0)
+	for i := 2; i < len(os.Args); i++ {
+		switch {
+		case os.Args[i] == "-n" && i+1 < len(os.Args):
+			i++
+			if _, err := fmt.Sscanf(os.Args[i], "%d", &count); err != nil {
+				fmt.Println(usage)
+				return
+			}
+		case os.Args[i] == "--seed" && i+1 < len(os.Args):
+			i++
+			if _, err := fmt.Sscanf(os.Args[i], "%d", &seed); err != nil {
+				fmt.Println(usage)
+				return
+			}
+		case os.Args[i] == "--pitch" && i+1 < len(os.Args):
+			i++
+			if _, err := fmt.Sscanf(os.Args[i], "%d", &position.Pitch); err != nil {
+				fmt.Println(usage)
+				return
+			}
+		case os.Args[i] == "--simulate":
+			follow = false
+		case !strings.HasPrefix(os.Args[i], "--"):
+			args = append(args, os.Args[i])
+		}
+	}
+	if len(args) != 1 && len(args) != 3 {
+		fmt.Println(usage)
+		return
+	}
+
+	var game *Game
+	for i := range games {
+		if games[i].ID == args[0] {
+			game = &games[i]
+		}
+	}
+	if game == nil {
+		fmt.Println("No game found with id", args[0])
+		return
+	}
+
+	if len(args) == 1 {
+		for i, play := range game.Plays {
+			fmt.Printf("%4d  %-6s %-4s %-22s %s\n", i, []string{"top", "bottom"}[play.Team], ordinal(play.Inning), LookupPerson(play.BatterID).FullName(), play.Event.Source)
+		}
+		return
+	}
+	if _, err := fmt.Sscanf(args[1], "%d", &position.Play); err != nil {
+		fmt.Println("The play must be a number, run whatif with just the game id to list them")
+		return
+	}
+
+	model := BuildSimulationModel(games)
+	rng := rand.New(rand.NewSource(seed))
+	result := SimulationResult{}
+	alternates := make([]Game, 0)
+	var first WhatIf
+	for i := 0; i < count; i++ {
+		whatIf, err := model.Resimulate(game, position, args[2], follow, rng)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if i == 0 {
+			first = whatIf
+		}
+		result.Add(whatIf.Game)
+		alternates = append(alternates, whatIf.Game)
+	}
+
+	// the narrative is only written in English, so the linescores are too
+	fmt.Println(WhatIfText(*game, first, TextOptions{Catalog: Catalogs["en"]}))
+	if count > 1 {
+		fmt.Printf("Played out %d times, seed %d\n", result.Games, seed)
+		for team, name := range []string{game.Info.Visteam.Name, game.Info.Hometeam.Name} {
+			fmt.Printf("%-20s %5d wins  %5.3f  %5.2f runs a game\n", name, result.Wins[team], result.WinPercentage(team), result.AverageRuns(team))
+		}
+	}
+
+	path := fmt.Sprintf("%s/%s.whatif.EVN", outputPath, game.ID)
+	if err := writeEventFile(path, alternates); err != nil {
+		fmt.Println("Error writing the games", err)
+		return
+	}
+	fmt.Println("Written to", path)
+}
+
+// printNarrative prints the play by play of the game with the id given after the command
+func printNarrative(games []Game) {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: narrative <game id>")
+		return
+	}
+
+	for _, game := range games {
+		if game.ID == os.Args[2] {
+			fmt.Println(NarrativeText(game))
+			return
+		}
+	}
+
+	fmt.Println("No game found with id", os.Args[2])
+}
+
+// writeEventFileCopy writes the games back out as an event file and reports whether it matches the one they were read from
+func writeEventFileCopy(eventData []byte, games []Game) {
+	copyPath := fmt.Sprintf("%s/%s", outputPath, filepath.Base(eventPath))
+	if err := writeEventFile(copyPath, games); err != nil {
+		fmt.Println("Error writing event file", err)
+		return
+	}
+
+	if CreateEventFile(games) == string(eventData) {
+		fmt.Println("Wrote", copyPath, "identical to", eventPath)
+	} else {
+		fmt.Println("Wrote", copyPath, "which differs from", eventPath)
+	}
+}
+
+// lintFiles checks the event files given after the command, or the hard-coded one, and lists every issue found.
+// The program exits with status 1 when there are issues so scripts can stop on them.
+func lintFiles() {
+	files := os.Args[2:]
+	if len(files) == 0 {
+		files = []string{eventPath}
+	}
+
+	count := 0
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			fmt.Println("Error reading event file", err)
+			os.Exit(1)
+		}
+
+		for _, issue := range LintEventFile(file, data) {
+			fmt.Println(issue)
+			count++
+		}
+	}
+
+	fmt.Printf("%d issues in %d files\n", count, len(files))
+	if count > 0 {
+		os.Exit(1)
+	}
+}
+
+// scoreGame scores a game interactively, prompting for the game details and lineups and then each play.
+// Add --color or --ascii to change how the scorecards are drawn and --lang=<code> to pick the language, as with the text command.
+// --serve=<address> publishes each play as a live feed that clients can follow while the game is scored.
+func scoreGame() {
+	catalog, err := languageOption()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	options := TextOptions{Catalog: catalog}
+	addr := ""
+	for _, arg := range os.Args[2:] {
+		switch {
+		case arg == "--color":
+			options.Color = true
+		case arg == "--ascii":
+			options.ASCII = true
+		case strings.HasPrefix(arg, "--serve="):
+			addr = strings.TrimPrefix(arg, "--serve=")
+		}
+	}
+
+	if err := os.MkdirAll(outputPath, 0755); err != nil {
+		fmt.Println("Error creating output directory", err)
+		return
+	}
+
+	scorer := CreateScorer(os.Stdin, os.Stdout, options)
+	hub := CreateFeedHub()
+	if addr != "" {
+		scorer.Broadcast(hub)
+		go func() {
+			if err := http.ListenAndServe(addr, CreateAPIServer(nil, hub).Handler()); err != nil {
+				fmt.Println("Error serving feed", err)
+			}
+		}()
+	}
+	if !scorer.Setup() {
+		return
+	}
+	if addr != "" {
+		host := addr
+		if strings.HasPrefix(host, ":") {
+			host = "localhost" + host
+		}
+		fmt.Printf("Follow the game at http://%s/games/%s/feed\n", host, scorer.game.ID)
+	}
+	scorer.Run()
+	// give anyone following along the chance to hear the game is over before the server goes away
+	hub.Drain(2 * time.Second)
+}
+
+// peopleSearchLimit how many people a search lists at most
+const peopleSearchLimit = 25
+
+// searchPeople finds people by name, by the start of their name with --prefix, or by id with --id, and prints them with their biographies
+func searchPeople() {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: people <name> | people --prefix <start of name> | people --id <id>")
+		return
+	}
+
+	index := CreatePeopleIndex()
+	var found []Person
+
+	switch os.Args[2] {
+	case "--id":
+		if len(os.Args) < 4 {
+			fmt.Println("Usage: people --id <id>")
+			return
+		}
+		person, _, ok := index.Lookup(os.Args[3])
+		if ok {
+			found = []Person{person}
+		}
+	case "--prefix":
+		found = index.Prefix(strings.Join(os.Args[3:], " "), peopleSearchLimit)
+	default:
+		found = index.Search(strings.Join(os.Args[2:], " "), peopleSearchLimit)
+	}
+
+	if len(found) == 0 {
+		fmt.Println("No people found")
+		return
+	}
 
-		// dump to disk as well
-		game.toDisk(outputPath)
+	for _, person := range found {
+		bio := Biographies[person.ID]
+		details := make([]string, 0)
+		for _, detail := range []struct{ label, value string }{
+			{"born", bio.BirthDate},
+			{"debut", bio.Debut},
+			{"final game", bio.FinalGame},
+			{"bats", bio.Bats},
+			{"throws", bio.Throws},
+			{"roles", strings.Join(bio.Roles, "/")},
+		} {
+			if detail.value != "" {
+				details = append(details, fmt.Sprintf("%s %s", detail.label, detail.value))
+			}
+		}
+		fmt.Println(strings.TrimSpace(fmt.Sprintf("%-10s %-30s %s", person.ID, person.FullName(), strings.Join(details, ", "))))
 	}
 }
@@ -0,0 +1,486 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Catalog the messages of one language. Messages are keyed by the English text they translate, gettext style, so
+// English needs no entries and anything a catalog is missing falls back to English.
+type Catalog struct {
+	Language string
+	Name     string
+	// Messages translates play descriptions and labels, formats can use %[n]s to put arguments in another order
+	Messages map[string]string
+	// Positions translates the codes and names of FieldingPositions, keyed by position number
+	Positions map[string]FieldingPosition
+	// Bases translates Bases, keyed by base code
+	Bases map[string]string
+	// Modifiers translates PlayModifiers, keyed by modifier code
+	Modifiers map[string]string
+}
+
+// Text translates a message
+func (catalog Catalog) Text(message string) string {
+	if translation, ok := catalog.Messages[message]; ok {
+		return translation
+	}
+
+	return message
+}
+
+// Sprintf translates a format before filling it in
+func (catalog Catalog) Sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(catalog.Text(format), args...)
+}
+
+// Position translates a fielding position, a field the catalog leaves blank keeps its English value
+func (catalog Catalog) Position(id string) FieldingPosition {
+	position := FieldingPositions[id]
+	if translation, ok := catalog.Positions[id]; ok {
+		if translation.Code != "" {
+			position.Code = translation.Code
+		}
+		if translation.Name != "" {
+			position.Name = translation.Name
+		}
+	}
+
+	return position
+}
+
+// Base translates the name of a base
+func (catalog Catalog) Base(code string) string {
+	if translation, ok := catalog.Bases[code]; ok {
+		return translation
+	}
+
+	return Bases[code]
+}
+
+// Modifier translates the description of a play modifier
+func (catalog Catalog) Modifier(code string) string {
+	if translation, ok := catalog.Modifiers[code]; ok {
+		return translation
+	}
+
+	return PlayModifiers[code]
+}
+
+// DescribeModifiers describes modifier codes as a comma-delimited list in parentheses, blank when there aren't any
+func (catalog Catalog) DescribeModifiers(modifiers []string) string {
+	if len(modifiers) == 0 {
+		return ""
+	}
+
+	descriptions := make([]string, 0)
+	for _, modifier := range modifiers {
+		descriptions = append(descriptions, catalog.Modifier(modifier))
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(descriptions, ","))
+}
+
+// Catalogs every language descriptions can be written in, keyed by language code
+var Catalogs = map[string]Catalog{
+	"en": Catalog{Language: "en", Name: "English"},
+	"es": Catalog{
+		Language: "es",
+		Name:     "Español",
+		Messages: map[string]string{
+			"strike out":                          "ponche",
+			"fly ball out %s":                     "elevado de out al %s",
+			"ground ball %s to %s":                "rodado al %s, out en %s",
+			"no play":                             "sin jugada",
+			"wild pitch":                          "lanzamiento descontrolado",
+			"stole %s":                            "se robó %s",
+			"single":                              "sencillo",
+			"single to %s":                        "sencillo al %s",
+			"strike out, %s %s":                   "ponche, %s %s",
+			"strike out, put out by %s %s":        "ponche, out por el %s %s",
+			"double":                              "doble",
+			"double to %s":                        "doble al %s",
+			"ground rule double":                  "doble por regla de terreno",
+			"triple":                              "triple",
+			"triple to %s":                        "triple al %s",
+			"home run %s":                         "jonrón %s",
+			"fielder's choice":                    "selección del defensor",
+			"fielder's choice to %s":              "selección del defensor, %s",
+			"error by %s %s":                      "error del %s %s",
+			"error by %s on throw from %s":        "error del %s en el tiro del %s",
+			"error by %s on foul fly ball":        "error del %s en elevado de foul",
+			"walk":                                "base por bolas",
+			"walk, %s":                            "base por bolas, %s",
+			"intentional walk":                    "base por bolas intencional",
+			"intentional walk, %s":                "base por bolas intencional, %s",
+			"hit by pitch":                        "golpeado por lanzamiento",
+			"interference %s":                     "interferencia %s",
+			"caught stealing %s":                  "atrapado robando %s",
+			"picked off at %s (caught stealing)":  "sorprendido en %s (atrapado robando)",
+			"pick off attempt at %s, error by %s": "intento de sorprender en %s, error del %s",
+			"picked off at %s":                    "sorprendido en %s",
+			"passed ball":                         "passed ball",
+			"balk":                                "balk",
+			"defensive indifference":              "indiferencia defensiva",
+			"base runner advance":                 "avance del corredor",
+			"ground ball %s":                      "rodado %s",
+			"force out %s":                        "out forzado %s",
+			"double play %s":                      "doble play %s",
+			"triple play %s":                      "triple play %s",
+			" to ":                                " a ",
+			"R / H":                               "C / H",
+			"    R  H  E":                         "    C  H  E",
+			"  AB   R   H RBI  BB  SO":            "  VB   C   H  CI  BB   K",
+			"Pitching":                            "Lanzadores",
+			"  IP   H   R  ER  BB  SO":            "  EL   H   C  CL  BB   K",
+
+			// the labels of the drawn scorecards, the html pages and scoring
+			"%s at %s":                            "%s visita a %s",
+			"%s at %s, %s, %s, %s":                "%s visita a %s, %s, %s, %s",
+			"Attendance: %d":                      "Asistencia: %d",
+			"Attendance: not recorded":            "Asistencia: sin registrar",
+			"Time: %d:%02d":                       "Duración: %d:%02d",
+			"Time: not recorded":                  "Duración: sin registrar",
+			"Weather: %s":                         "Clima: %s",
+			"Weather: not recorded":               "Clima: sin registrar",
+			"wind %d mph":                         "viento %d mph",
+			"Umpires: %s":                         "Árbitros: %s",
+			"Batter":                              "Bateador",
+			"Pitchers: %s":                        "Lanzadores: %s",
+			"%d RBI":                              "%d CI",
+			"R":                                   "C",
+			"AB":                                  "VB",
+			"RBI":                                 "CI",
+			"SO":                                  "K",
+			"IP":                                  "EL",
+			"ER":                                  "CL",
+			"%s season":                           "Temporada %s",
+			"Linescore":                           "Marcador",
+			"Box score":                           "Resumen del juego",
+			"Top":                                 "Alta",
+			"Bottom":                              "Baja",
+			"%s %s: %s facing %s":                 "%s %s: %s contra %s",
+			"Count: %s":                           "Cuenta: %s",
+			"Count: not recorded":                 "Cuenta: sin registrar",
+			"No pitch sequence recorded":          "Sin secuencia de lanzamientos",
+			"Before: %s":                          "Antes: %s",
+			"After: %s":                           "Después: %s",
+			"Runs: %d   RE24: %+.2f   WPA: %+.3f": "Carreras: %d   RE24: %+.2f   WPA: %+.3f",
+			"%s, %s, score %d-%d":                 "%s, %s, marcador %d-%d",
+			"%s on %s":                            "%s en %s",
+			"%d outs":                             "%d outs",
+			"1 out":                               "1 out",
+			"bases empty":                         "bases vacías",
+			"%s %d: %s. %s up":                    "%s %d: %s. Batea %s",
+		},
+		Positions: map[string]FieldingPosition{
+			"1":  FieldingPosition{Name: "Lanzador"},
+			"2":  FieldingPosition{Name: "Receptor"},
+			"3":  FieldingPosition{Name: "Primera Base"},
+			"4":  FieldingPosition{Name: "Segunda Base"},
+			"5":  FieldingPosition{Name: "Tercera Base"},
+			"6":  FieldingPosition{Name: "Campocorto"},
+			"7":  FieldingPosition{Name: "Jardín Izquierdo"},
+			"8":  FieldingPosition{Name: "Jardín Central"},
+			"9":  FieldingPosition{Name: "Jardín Derecho"},
+			"10": FieldingPosition{Name: "Bateador Designado"},
+			"11": FieldingPosition{Name: "Bateador Emergente"},
+			"12": FieldingPosition{Name: "Corredor Emergente"},
+		},
+		Bases: map[string]string{
+			"1": "primera",
+			"2": "segunda",
+			"3": "tercera",
+			"H": "home",
+		},
+		Modifiers: map[string]string{
+			"AP":   "jugada de apelación",
+			"BP":   "toque de elevado",
+			"BG":   "toque de rodado",
+			"BGDP": "toque para doble play",
+			"BINT": "interferencia del bateador",
+			"BL":   "toque de línea",
+			"BOOT": "bateo fuera de turno",
+			"BR":   "corredor golpeado por la bola bateada",
+			"C":    "tercer strike cantado",
+			"COUB": "bateador de cortesía",
+			"COUF": "defensor de cortesía",
+			"COUR": "corredor de cortesía",
+			"DP":   "doble play",
+			"E1":   "error del lanzador",
+			"E2":   "error del receptor",
+			"E3":   "error del primera base",
+			"E4":   "error del segunda base",
+			"E5":   "error del tercera base",
+			"E6":   "error del campocorto",
+			"E7":   "error del jardinero izquierdo",
+			"E8":   "error del jardinero central",
+			"E9":   "error del jardinero derecho",
+			"F":    "elevado",
+			"FDP":  "elevado para doble play",
+			"FINT": "interferencia de un aficionado",
+			"FL":   "foul",
+			"FO":   "out forzado",
+			"G":    "rodado",
+			"GDP":  "rodado para doble play",
+			"GTP":  "rodado para triple play",
+			"IF":   "regla de infield fly",
+			"INT":  "interferencia",
+			"IPHR": "jonrón dentro del parque",
+			"L":    "línea",
+			"LDP":  "línea para doble play",
+			"LTP":  "línea para triple play",
+			"MREV": "revisión pedida por el mánager",
+			"NDP":  "sin doble play acreditado",
+			"OBS":  "obstrucción de un defensor al corredor",
+			"P":    "elevado al cuadro",
+			"PASS": "un corredor pasó a otro y fue declarado out",
+			"R1":   "relevo al lanzador sin out",
+			"R2":   "relevo al receptor sin out",
+			"R3":   "relevo al primera base sin out",
+			"R4":   "relevo al segunda base sin out",
+			"R5":   "relevo al tercera base sin out",
+			"R6":   "relevo al campocorto sin out",
+			"R7":   "relevo al jardinero izquierdo sin out",
+			"R8":   "relevo al jardinero central sin out",
+			"R9":   "relevo al jardinero derecho sin out",
+			"RINT": "interferencia del corredor",
+			"SF":   "elevado de sacrificio",
+			"SH":   "toque de sacrificio",
+			"TH":   "tiro",
+			"TH1":  "tiro al lanzador",
+			"TH2":  "tiro al receptor",
+			"TH3":  "tiro al primera base",
+			"TH4":  "tiro al segunda base",
+			"TH5":  "tiro al tercera base",
+			"TH6":  "tiro al campocorto",
+			"TH7":  "tiro al jardinero izquierdo",
+			"TH8":  "tiro al jardinero central",
+			"TH9":  "tiro al jardinero derecho",
+			"TP":   "triple play",
+			"UINT": "interferencia del umpire",
+			"UREV": "revisión de los umpires",
+		},
+	},
+	"ja": Catalog{
+		Language: "ja",
+		Name:     "日本語",
+		Messages: map[string]string{
+			"strike out":                          "三振",
+			"fly ball out %s":                     "%s飛",
+			"ground ball %s to %s":                "%s→%sのゴロ",
+			"no play":                             "プレーなし",
+			"wild pitch":                          "暴投",
+			"stole %s":                            "%s盗塁",
+			"single":                              "単打",
+			"single to %s":                        "%s安",
+			"strike out, %s %s":                   "三振、%s %s",
+			"strike out, put out by %s %s":        "三振、%sが刺殺 %s",
+			"double":                              "二塁打",
+			"double to %s":                        "%s二",
+			"ground rule double":                  "エンタイトルツーベース",
+			"triple":                              "三塁打",
+			"triple to %s":                        "%s三",
+			"home run %s":                         "本塁打 %s",
+			"fielder's choice":                    "野選",
+			"fielder's choice to %s":              "%s野選",
+			"error by %s %s":                      "%s失 %s",
+			"error by %s on throw from %s":        "%[2]sからの送球を%[1]sが失策",
+			"error by %s on foul fly ball":        "%sファウルフライ失策",
+			"walk":                                "四球",
+			"walk, %s":                            "四球、%s",
+			"intentional walk":                    "故意四球",
+			"intentional walk, %s":                "故意四球、%s",
+			"hit by pitch":                        "死球",
+			"interference %s":                     "打撃妨害 %s",
+			"caught stealing %s":                  "%s盗塁死",
+			"picked off at %s (caught stealing)":  "%sで牽制死（盗塁死）",
+			"pick off attempt at %s, error by %s": "%s牽制、%s失策",
+			"picked off at %s":                    "%sで牽制死",
+			"passed ball":                         "捕逸",
+			"balk":                                "ボーク",
+			"defensive indifference":              "無関心進塁",
+			"base runner advance":                 "走者の進塁",
+			"ground ball %s":                      "%sゴロ",
+			"force out %s":                        "%s封殺",
+			"double play %s":                      "%s併殺",
+			"triple play %s":                      "%s三重殺",
+			" to ":                                "→",
+			// kanji are two columns wide in a terminal, so the labels are spaced to line up with the numbers below them
+			"R / H":                    "点/安",
+			"    R  H  E":              "   得 安 失",
+			"  AB   R   H RBI  BB  SO": "打数得点安打打点四球三振",
+			"Pitching":                 "投手",
+			"  IP   H   R  ER  BB  SO": "投回被安失点自責四球三振",
+
+			// the labels of the drawn scorecards, the html pages and scoring
+			"%s at %s":                            "%s 対 %s",
+			"%s at %s, %s, %s, %s":                "%s 対 %s、%s、%s、%s",
+			"Attendance: %d":                      "観衆: %d人",
+			"Attendance: not recorded":            "観衆: 記録なし",
+			"Time: %d:%02d":                       "試合時間: %d:%02d",
+			"Time: not recorded":                  "試合時間: 記録なし",
+			"Weather: %s":                         "天候: %s",
+			"Weather: not recorded":               "天候: 記録なし",
+			"wind %d mph":                         "風速%dマイル",
+			"Umpires: %s":                         "審判: %s",
+			"Batter":                              "打者",
+			"Pitchers: %s":                        "投手: %s",
+			"%d RBI":                              "%d打点",
+			"R":                                   "得",
+			"H":                                   "安",
+			"E":                                   "失",
+			"AB":                                  "打数",
+			"RBI":                                 "打点",
+			"BB":                                  "四球",
+			"SO":                                  "三振",
+			"IP":                                  "投回",
+			"ER":                                  "自責",
+			"%s season":                           "%sシーズン",
+			"Linescore":                           "スコアボード",
+			"Box score":                           "ボックススコア",
+			"Top":                                 "表",
+			"Bottom":                              "裏",
+			"%s %s: %s facing %s":                 "%[2]s%[1]s: %[3]s 対 %[4]s",
+			"Count: %s":                           "カウント: %s",
+			"Count: not recorded":                 "カウント: 記録なし",
+			"No pitch sequence recorded":          "投球の記録なし",
+			"Before: %s":                          "プレー前: %s",
+			"After: %s":                           "プレー後: %s",
+			"Runs: %d   RE24: %+.2f   WPA: %+.3f": "得点: %d   RE24: %+.2f   WPA: %+.3f",
+			"%s, %s, score %d-%d":                 "%s、%s、%d対%d",
+			"%s on %s":                            "%[2]sに%[1]s",
+			"%d outs":                             "%dアウト",
+			"1 out":                               "1アウト",
+			"bases empty":                         "走者なし",
+			"%s %d: %s. %s up":                    "%[2]d回%[1]s: %[3]s。打者 %[4]s",
+		},
+		Positions: map[string]FieldingPosition{
+			"1":  FieldingPosition{Code: "投", Name: "投手"},
+			"2":  FieldingPosition{Code: "捕", Name: "捕手"},
+			"3":  FieldingPosition{Code: "一", Name: "一塁手"},
+			"4":  FieldingPosition{Code: "二", Name: "二塁手"},
+			"5":  FieldingPosition{Code: "三", Name: "三塁手"},
+			"6":  FieldingPosition{Code: "遊", Name: "遊撃手"},
+			"7":  FieldingPosition{Code: "左", Name: "左翼手"},
+			"8":  FieldingPosition{Code: "中", Name: "中堅手"},
+			"9":  FieldingPosition{Code: "右", Name: "右翼手"},
+			"10": FieldingPosition{Code: "指", Name: "指名打者"},
+			"11": FieldingPosition{Code: "打", Name: "代打"},
+			"12": FieldingPosition{Code: "走", Name: "代走"},
+		},
+		Bases: map[string]string{
+			"1": "一塁",
+			"2": "二塁",
+			"3": "三塁",
+			"H": "本塁",
+		},
+		Modifiers: map[string]string{
+			"AP":   "アピールプレー",
+			"BP":   "バントの飛球",
+			"BG":   "バントのゴロ",
+			"BGDP": "バント併殺",
+			"BINT": "打者の妨害",
+			"BL":   "バントのライナー",
+			"BOOT": "打順誤り",
+			"BR":   "打球が走者に当たる",
+			"C":    "見逃し三振",
+			"COUB": "代理打者",
+			"COUF": "代理野手",
+			"COUR": "代理走者",
+			"DP":   "併殺",
+			"E1":   "投手の失策",
+			"E2":   "捕手の失策",
+			"E3":   "一塁手の失策",
+			"E4":   "二塁手の失策",
+			"E5":   "三塁手の失策",
+			"E6":   "遊撃手の失策",
+			"E7":   "左翼手の失策",
+			"E8":   "中堅手の失策",
+			"E9":   "右翼手の失策",
+			"F":    "飛球",
+			"FDP":  "飛球併殺",
+			"FINT": "観客の妨害",
+			"FL":   "ファウル",
+			"FO":   "封殺",
+			"G":    "ゴロ",
+			"GDP":  "ゴロ併殺",
+			"GTP":  "ゴロ三重殺",
+			"IF":   "インフィールドフライ",
+			"INT":  "妨害",
+			"IPHR": "ランニングホームラン",
+			"L":    "ライナー",
+			"LDP":  "ライナー併殺",
+			"LTP":  "ライナー三重殺",
+			"MREV": "監督のリクエスト",
+			"NDP":  "併殺の記録なし",
+			"OBS":  "走塁妨害",
+			"P":    "内野飛球",
+			"PASS": "前の走者を追い越してアウト",
+			"R1":   "投手への中継、アウトなし",
+			"R2":   "捕手への中継、アウトなし",
+			"R3":   "一塁手への中継、アウトなし",
+			"R4":   "二塁手への中継、アウトなし",
+			"R5":   "三塁手への中継、アウトなし",
+			"R6":   "遊撃手への中継、アウトなし",
+			"R7":   "左翼手への中継、アウトなし",
+			"R8":   "中堅手への中継、アウトなし",
+			"R9":   "右翼手への中継、アウトなし",
+			"RINT": "走者の妨害",
+			"SF":   "犠飛",
+			"SH":   "犠打",
+			"TH":   "送球",
+			"TH1":  "投手への送球",
+			"TH2":  "捕手への送球",
+			"TH3":  "一塁手への送球",
+			"TH4":  "二塁手への送球",
+			"TH5":  "三塁手への送球",
+			"TH6":  "遊撃手への送球",
+			"TH7":  "左翼手への送球",
+			"TH8":  "中堅手への送球",
+			"TH9":  "右翼手への送球",
+			"TP":   "三重殺",
+			"UINT": "審判の妨害",
+			"UREV": "審判のリプレー検証",
+		},
+	},
+}
+
+// LookupCatalog Given a language code such as es, return its catalog
+func LookupCatalog(language string) (Catalog, error) {
+	if catalog, ok := Catalogs[strings.ToLower(language)]; ok {
+		return catalog, nil
+	}
+
+	languages := make([]string, 0)
+	for code := range Catalogs {
+		languages = append(languages, code)
+	}
+	sort.Strings(languages)
+
+	return Catalog{}, fmt.Errorf("no messages for language '%s', try one of %s", language, strings.Join(languages, ", "))
+}
+
+// Localize rewrites the play descriptions and position names of a game in the language of a catalog
+func (game *Game) Localize(catalog Catalog) {
+	game.Language = catalog.Language
+
+	// position codes are left alone, they're the notation the scorecards are drawn with
+	localize := func(player *Player) {
+		player.FieldingPosition.Name = catalog.Position(player.FieldingPosition.ID).Name
+	}
+	for _, lineup := range [][]Player{game.Lineup.Visitor, game.Lineup.Home} {
+		for i := range lineup {
+			localize(&lineup[i])
+		}
+	}
+
+	for i := range game.Plays {
+		play := &game.Plays[i]
+		play.Description = strings.TrimSpace(describePlay(play.Event.Source, catalog).description)
+		for j := range play.Substitutions {
+			localize(&play.Substitutions[j].Player)
+		}
+	}
+}
@@ -0,0 +1,446 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// positionNames what the players at each fielding position are called in a narrative
+var positionNames = map[int]string{
+	1: "pitcher",
+	2: "catcher",
+	3: "first baseman",
+	4: "second baseman",
+	5: "third baseman",
+	6: "shortstop",
+	7: "left fielder",
+	8: "center fielder",
+	9: "right fielder",
+}
+
+// hitDirections where a hit fielded by each position went
+var hitDirections = map[int]string{
+	1: "to the pitcher",
+	2: "to the catcher",
+	3: "to first",
+	4: "to second",
+	5: "to third",
+	6: "to short",
+	7: "to left",
+	8: "to center",
+	9: "to right",
+}
+
+// hitVerbs the verb for each kind of hit
+var hitVerbs = map[string]string{
+	"S":   "singles",
+	"D":   "doubles",
+	"T":   "triples",
+	"H":   "homers",
+	"HR":  "homers",
+	"DGR": "hits a ground rule double",
+}
+
+// outVerbs the verb for an out on each trajectory of batted ball
+var outVerbs = map[string]string{
+	"G": "grounds out",
+	"F": "flies out",
+	"L": "lines out",
+	"P": "pops out",
+}
+
+// plural writes a count with the singular or plural form of what is counted
+func plural(n int, singular string, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// narrator describes the plays of a game in sentences, keeping track of who is where on the field
+type narrator struct {
+	names   map[string]string
+	defense [11]string
+}
+
+// fullName the name of a player as the lineup has it
+func (n *narrator) fullName(id string) string {
+	if name, ok := n.names[id]; ok && name != "" {
+		return name
+	}
+
+	return LookupPerson(id).FullName()
+}
+
+// lastName the last name of a player, used for runners once the batter has been named in full
+func (n *narrator) lastName(id string) string {
+	if person, ok := People[id]; ok && person.LastName != "" {
+		return person.LastName
+	}
+	words := strings.Fields(n.fullName(id))
+	if len(words) == 0 {
+		return id
+	}
+
+	return words[len(words)-1]
+}
+
+// fielder a fielder by position and name, e.g. second baseman Tom Herr
+func (n *narrator) fielder(position int) string {
+	name := positionNames[position]
+	if id := n.defense[position]; id != "" {
+		name += " " + n.fullName(id)
+	}
+
+	return name
+}
+
+// fielders a chain of fielders handling the ball, e.g. shortstop Rafael Santana to first baseman Keith Hernandez
+func (n *narrator) fielders(sequence string) string {
+	described := make([]string, 0)
+	for i, r := range sequence {
+		if r < '1' || r > '9' || (i > 0 && sequence[i-1] == sequence[i]) {
+			continue
+		}
+		described = append(described, n.fielder(int(r-'0')))
+	}
+
+	return strings.Join(described, " to ")
+}
+
+// outFielders the fielders on the batter's out, every fielder in the play for a double or triple play
+func outFielders(event Event, primary string) string {
+	segments := fieldingOutSegments.FindAllStringSubmatch(primary, -1)
+	sequence := ""
+	for i, segment := range segments {
+		if event.DoublePlay || event.TriplePlay || segment[2] == "B" || (segment[2] == "" && i == len(segments)-1) {
+			sequence += segment[1]
+		}
+	}
+
+	return sequence
+}
+
+// batterClause describes what happened to the batter, or the main event of the play when it didn't involve the batter
+func (n *narrator) batterClause(play Play, primary string) string {
+	event := play.Event
+	batter := n.fullName(play.BatterID)
+	trajectory, bunt, _ := battedBall(event)
+
+	switch {
+	case event.Type == EventStrikeout:
+		pitches := strings.TrimRight(play.Pitches, ".123>+*N")
+		switch {
+		case strings.HasSuffix(pitches, "C"):
+			return batter + " strikes out looking"
+		case strings.HasSuffix(pitches, "S"):
+			return batter + " strikes out swinging"
+		}
+		return batter + " strikes out"
+	case event.Type == EventWalk:
+		return batter + " walks"
+	case event.Type == EventIntentionalWalk:
+		return batter + " is intentionally walked"
+	case event.Type == EventHitByPitch:
+		return batter + " is hit by a pitch"
+	case event.Type == EventInterference:
+		return batter + " reaches on catcher's interference"
+	case event.Type >= EventSingle && event.Type <= EventHomeRun:
+		matches := hitMatcher.FindStringSubmatch(primary)
+		clause := batter + " " + hitVerbs[matches[1]]
+		if fielder := fieldedBy(event); fielder > 0 && matches[1] != "DGR" {
+			clause += " " + hitDirections[fielder]
+		}
+		if bunt {
+			clause += " on a bunt"
+		}
+		return clause
+	case event.Type == EventError:
+		return batter + " reaches on an error by " + n.fielder(event.Errors[0])
+	case event.Type == EventFoulError:
+		return batter + "'s foul fly is dropped by " + n.fielder(event.Errors[0])
+	case event.Type == EventFieldersChoice:
+		if fielder := fieldedBy(event); fielder > 0 {
+			return batter + " reaches on a fielder's choice by " + n.fielder(fielder)
+		}
+		return batter + " reaches on a fielder's choice"
+	case event.Type == EventGenericOut:
+		sequence := outFielders(event, primary)
+		switch {
+		case sequence == "":
+			// the out was made on a runner and the batter is safe at first
+			for _, segment := range fieldingOutSegments.FindAllStringSubmatch(primary, -1) {
+				sequence += segment[1]
+			}
+			return batter + " reaches on a force out, " + n.fielders(sequence)
+		case event.SacFly:
+			return batter + " hits a sacrifice fly to " + n.fielders(sequence)
+		case event.Sacrifice:
+			return batter + " sacrifices, " + n.fielders(sequence)
+		case event.TriplePlay:
+			return batter + " hits into a triple play, " + n.fielders(sequence)
+		case event.DoublePlay:
+			verb := map[string]string{"F": "flies", "L": "lines", "P": "pops"}[trajectory]
+			if verb == "" {
+				verb = "grounds"
+			}
+			return batter + " " + verb + " into a double play, " + n.fielders(sequence)
+		}
+
+		verb := outVerbs[trajectory]
+		if verb == "" {
+			verb = "pops out"
+			if len(sequence) > 1 {
+				verb = "grounds out"
+			} else if sequence >= "7" {
+				verb = "flies out"
+			}
+		}
+		if len(sequence) == 1 {
+			if verb == "grounds out" {
+				return batter + " grounds out to " + n.fielders(sequence) + " unassisted"
+			}
+			return batter + " " + verb + " to " + n.fielders(sequence)
+		}
+		return batter + " " + verb + ", " + n.fielders(sequence)
+	}
+
+	return ""
+}
+
+// runnerClause describes a play on a runner such as a stolen base, or a wild pitch, balk or passed ball, naming the runner
+// when there is one so they aren't described again. told collects the runners that were described.
+func (n *narrator) runnerClause(play Play, code string, told map[string]bool) string {
+	runner := func(base int) string {
+		if base < 1 || base > 3 {
+			return ""
+		}
+		id := play.Before.Bases[base-1]
+		told[id] = true
+		return n.lastName(id)
+	}
+
+	switch {
+	case stolenBaseMatcher.MatchString(code):
+		base := BaseNumber(stolenBaseMatcher.FindStringSubmatch(code)[1])
+		if base == Home {
+			return runner(3) + " steals home"
+		}
+		return runner(base-1) + " steals " + Bases[BaseCode(base)]
+	case caughtStealing.MatchString(code):
+		matches := caughtStealing.FindStringSubmatch(code)
+		base := BaseNumber(matches[2])
+		if matches[1] == "PO" {
+			return runner(base-1) + " is picked off and caught stealing " + Bases[BaseCode(base)]
+		}
+		if errorCredits.MatchString(matches[3]) {
+			return runner(base-1) + " is safe stealing " + Bases[BaseCode(base)] + " on an error"
+		}
+		return runner(base-1) + " is caught stealing " + Bases[BaseCode(base)]
+	case pickoffMatcher.MatchString(code):
+		matches := pickoffMatcher.FindStringSubmatch(code)
+		base := BaseNumber(matches[1])
+		if errorCredits.MatchString(matches[2]) {
+			return "pickoff throw to " + Bases[BaseCode(base)] + " is wild"
+		}
+		return runner(base) + " is picked off " + Bases[BaseCode(base)]
+	case code == "WP":
+		return "wild pitch by " + n.fullName(play.PitcherID)
+	case code == "PB":
+		return "passed ball by " + n.fielder(2)
+	case code == "BK":
+		return "balk by " + n.fullName(play.PitcherID)
+	case code == "DI":
+		return "defensive indifference"
+	case code == "OA":
+		return "the runners move up"
+	}
+
+	return ""
+}
+
+// runnerMoves describes where the runners, and the batter when it isn't obvious, ended up
+func (n *narrator) runnerMoves(play Play, told map[string]bool) []string {
+	moves := make([]string, 0)
+	outs := make(map[int]int)
+	for _, advance := range play.Event.Advances {
+		if advance.Out {
+			outs[advance.From] = advance.To
+		}
+	}
+
+	for base := 3; base >= 1; base-- {
+		id := play.Before.Bases[base-1]
+		if id == "" || told[id] {
+			continue
+		}
+		if move := n.move(play, id, base, outs); move != "" {
+			moves = append(moves, move)
+		}
+	}
+
+	// the batter reaching further than the play itself gets them, such as taking second on the throw
+	if play.Event.Type != EventGenericOut && play.Event.Type != EventStrikeout && play.Event.Type != EventHomeRun {
+		reached := 1
+		if value := play.Event.HitValue(); value > 0 {
+			reached = value
+		}
+		for base, id := range play.After.Bases {
+			if id == play.BatterID && base+1 != reached {
+				moves = append(moves, n.lastName(id)+" to "+Bases[BaseCode(base+1)])
+			}
+		}
+		if Contains(play.Scorers, play.BatterID) {
+			moves = append(moves, n.lastName(play.BatterID)+" scores")
+		}
+	}
+	if play.Event.Type == EventStrikeout {
+		for base, id := range play.After.Bases {
+			if id == play.BatterID {
+				moves = append(moves, n.lastName(id)+" reaches "+Bases[BaseCode(base+1)])
+			}
+		}
+	}
+
+	return moves
+}
+
+// move describes where a runner who started the play on a base ended up, blank when they stayed put
+func (n *narrator) move(play Play, id string, base int, outs map[int]int) string {
+	if Contains(play.Scorers, id) {
+		return n.lastName(id) + " scores"
+	}
+	for after, runner := range play.After.Bases {
+		if runner == id {
+			if after+1 == base {
+				return ""
+			}
+			return n.lastName(id) + " advances to " + Bases[BaseCode(after+1)]
+		}
+	}
+	if to, ok := outs[base]; ok {
+		return n.lastName(id) + " is out at " + Bases[BaseCode(to)]
+	}
+
+	return n.lastName(id) + " is out at " + Bases[BaseCode(base+1)]
+}
+
+// describe writes the play as a sentence
+func (n *narrator) describe(play Play) string {
+	if play.Event.Type == EventNone {
+		return ""
+	}
+
+	parts := strings.SplitN(play.Event.BasicPlay, "+", 2)
+	told := map[string]bool{play.BatterID: true}
+	clauses := make([]string, 0)
+
+	if clause := n.batterClause(play, parts[0]); clause != "" {
+		clauses = append(clauses, clause)
+	} else if clause := n.runnerClause(play, parts[0], told); clause != "" {
+		clauses = append(clauses, clause)
+	}
+	if len(parts) == 2 {
+		for _, code := range strings.Split(parts[1], ";") {
+			if clause := n.runnerClause(play, code, told); clause != "" {
+				clauses = append(clauses, clause)
+			}
+		}
+	}
+	clauses = append(clauses, n.runnerMoves(play, told)...)
+	if len(clauses) == 0 {
+		return ""
+	}
+
+	sentence := strings.Join(clauses, "; ")
+	return strings.ToUpper(sentence[:1]) + sentence[1:] + "."
+}
+
+// describeSubstitution writes a substitution as a sentence
+func (n *narrator) describeSubstitution(sub Substitution) string {
+	name := n.fullName(sub.Player.ID)
+	replaced := n.fullName(sub.ReplacedID)
+
+	switch sub.Player.FieldingPosition.ID {
+	case "11":
+		return fmt.Sprintf("%s pinch hits for %s.", name, replaced)
+	case "12":
+		return fmt.Sprintf("%s pinch runs for %s.", name, replaced)
+	}
+
+	position := strings.ToLower(sub.Player.FieldingPosition.Name)
+	if sub.ReplacedID == "" || sub.ReplacedID == sub.Player.ID {
+		return fmt.Sprintf("%s moves to %s.", name, position)
+	}
+	if sub.Player.FieldingPosition.ID == "1" {
+		return fmt.Sprintf("%s comes in to pitch, replacing %s.", name, replaced)
+	}
+
+	return fmt.Sprintf("%s replaces %s, playing %s.", name, replaced, position)
+}
+
+// describeHalfInningSummary the runs, hits, errors and runners left on base in a half inning, as Retrosheet's box scores
+// give them, totalled by the linescore so the two always agree
+func describeHalfInningSummary(half []Play) string {
+	linescore := CreateLinescore(half)
+	team := half[0].Team
+
+	return fmt.Sprintf("%s, %s, %s, %d left on base", plural(linescore.Runs[team], "run", "runs"), plural(linescore.Hits[team], "hit", "hits"),
+		plural(linescore.Errors[1-team], "error", "errors"), linescore.LeftOnBase[team])
+}
+
+// NarrativeHalfInning the play by play of a half inning
+type NarrativeHalfInning struct {
+	Inning int    `json:"inning"`
+	Team   int    `json:"team"`
+	Title  string `json:"title"`
+	// Lines holds a sentence for each substitution and play in order
+	Lines   []string `json:"lines"`
+	Summary string   `json:"summary"`
+}
+
+// CreateNarrative Given a game, write its play by play in sentences with a summary of each half inning
+func CreateNarrative(game Game) []NarrativeHalfInning {
+	n := narrator{names: playerNames(game)}
+	state := NewGameState(game.Lineup)
+	halves := make([]NarrativeHalfInning, 0)
+
+	for _, half := range halfInnings(game.Plays) {
+		team := []string{game.Info.Visteam.Name, game.Info.Hometeam.Name}[half[0].Team]
+		narrative := NarrativeHalfInning{
+			Inning:  half[0].Inning,
+			Team:    half[0].Team,
+			Title:   fmt.Sprintf("%s of the %s, %s", []string{"Top", "Bottom"}[half[0].Team], ordinal(half[0].Inning), team),
+			Lines:   make([]string, 0),
+			Summary: describeHalfInningSummary(half),
+		}
+
+		for _, play := range half {
+			for _, sub := range play.Substitutions {
+				state.Substitute(sub)
+				narrative.Lines = append(narrative.Lines, n.describeSubstitution(sub))
+			}
+			n.defense = state.Defense[1-play.Team]
+			if sentence := n.describe(play); sentence != "" {
+				narrative.Lines = append(narrative.Lines, sentence)
+			}
+		}
+
+		halves = append(halves, narrative)
+	}
+
+	return halves
+}
+
+// NarrativeText the play by play of a game as plain text
+func NarrativeText(game Game) string {
+	lines := []string{describeResult(game), ""}
+	for _, half := range CreateNarrative(game) {
+		lines = append(lines, half.Title)
+		lines = append(lines, half.Lines...)
+		lines = append(lines, half.Summary, "")
+	}
+
+	return strings.Join(lines, "\n")
+}
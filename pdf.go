@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PDFPageSizes the supported paper sizes, in points and turned to landscape so the innings fit across
+var PDFPageSizes = map[string][2]float64{
+	"letter": {792, 612},
+	"a4":     {842, 595},
+}
+
+// pdfMargin the space kept clear around the edge of each page, in points
+const pdfMargin = 24
+
+// pdfIndexLines how many games are listed on each page of the index
+const pdfIndexLines = 32
+
+// helveticaWidths the advance widths of the printable ascii characters in Helvetica, in thousandths of the font size
+var helveticaWidths = []int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// pdfCanvas draws onto the content stream of a pdf page
+type pdfCanvas struct {
+	b strings.Builder
+}
+
+// pdfEncode converts text to the WinAnsi bytes used by the standard fonts, escaped for a pdf string
+func pdfEncode(text string) string {
+	b := strings.Builder{}
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case r >= 160 && r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+
+	return b.String()
+}
+
+// pdfTextWidth the width of text set in Helvetica at the given size
+func pdfTextWidth(text string, size float64) float64 {
+	width := 0
+	for _, r := range text {
+		if r >= 32 && r < 127 {
+			width += helveticaWidths[r-32]
+		} else {
+			width += 556
+		}
+	}
+
+	return float64(width) * size / 1000
+}
+
+// pdfColor converts a #rrggbb colour to pdf colour components
+func pdfColor(hex string) string {
+	value, _ := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+
+	return fmt.Sprintf("%.3f %.3f %.3f", float64(value>>16&0xff)/255, float64(value>>8&0xff)/255, float64(value&0xff)/255)
+}
+
+// path adds a path through the points to the content stream
+func (c *pdfCanvas) path(points [][2]float64) {
+	for i, p := range points {
+		op := "l"
+		if i == 0 {
+			op = "m"
+		}
+		fmt.Fprintf(&c.b, "%.2f %.2f %s\n", p[0], p[1], op)
+	}
+}
+
+func (c *pdfCanvas) Rect(x float64, y float64, width float64, height float64) {
+	fmt.Fprintf(&c.b, "%s RG 1 w %.2f %.2f %.2f %.2f re S\n", pdfColor("#333333"), x, y, width, height)
+}
+
+func (c *pdfCanvas) Polygon(points [][2]float64, fill string, stroke string, width float64) {
+	fmt.Fprintf(&c.b, "%s RG %.2f w\n", pdfColor(stroke), width)
+	c.path(points)
+	if fill == "" {
+		c.b.WriteString("h S\n")
+		return
+	}
+	fmt.Fprintf(&c.b, "%s rg h B\n", pdfColor(fill))
+}
+
+func (c *pdfCanvas) Polyline(points [][2]float64, stroke string, width float64) {
+	fmt.Fprintf(&c.b, "%s RG %.2f w 1 j\n", pdfColor(stroke), width)
+	c.path(points)
+	c.b.WriteString("S 0 j\n")
+}
+
+func (c *pdfCanvas) Circle(cx float64, cy float64, r float64, stroke string) {
+	// four bezier curves make a close enough circle
+	k := 0.5523 * r
+	fmt.Fprintf(&c.b, "%s RG 1 w %.2f %.2f m\n", pdfColor(stroke), cx+r, cy)
+	fmt.Fprintf(&c.b, "%.2f %.2f %.2f %.2f %.2f %.2f c\n", cx+r, cy+k, cx+k, cy+r, cx, cy+r)
+	fmt.Fprintf(&c.b, "%.2f %.2f %.2f %.2f %.2f %.2f c\n", cx-k, cy+r, cx-r, cy+k, cx-r, cy)
+	fmt.Fprintf(&c.b, "%.2f %.2f %.2f %.2f %.2f %.2f c\n", cx-r, cy-k, cx-k, cy-r, cx, cy-r)
+	fmt.Fprintf(&c.b, "%.2f %.2f %.2f %.2f %.2f %.2f c S\n", cx+k, cy-r, cx+r, cy-k, cx+r, cy)
+}
+
+func (c *pdfCanvas) Text(x float64, y float64, anchor string, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+
+	switch anchor {
+	case "middle":
+		x -= pdfTextWidth(text, size) / 2
+	case "end":
+		x -= pdfTextWidth(text, size)
+	}
+
+	// the page is flipped so y runs down, so the text has to be flipped back
+	fmt.Fprintf(&c.b, "0 g BT /%s %.1f Tf 1 0 0 -1 %.2f %.2f Tm (%s) Tj ET\n", font, size, x, y, pdfEncode(text))
+}
+
+// PDFDocument a collection of pages drawn for printing
+type PDFDocument struct {
+	width  float64
+	height float64
+	pages  []string
+}
+
+// NewPDFDocument Given one of the PDFPageSizes, return an empty document
+func NewPDFDocument(size string) (*PDFDocument, error) {
+	dimensions, ok := PDFPageSizes[strings.ToLower(size)]
+	if !ok {
+		return nil, fmt.Errorf("unknown page size '%s'", size)
+	}
+
+	return &PDFDocument{width: dimensions[0], height: dimensions[1]}, nil
+}
+
+// AddPage adds a page holding a drawing of the given size, shrunk as needed to fit inside the margins
+func (doc *PDFDocument) AddPage(width float64, height float64, draw func(c canvas)) {
+	scale := 1.0
+	if fit := (doc.width - 2*pdfMargin) / width; fit < scale {
+		scale = fit
+	}
+	if fit := (doc.height - 2*pdfMargin) / height; fit < scale {
+		scale = fit
+	}
+
+	c := &pdfCanvas{}
+	fmt.Fprintf(&c.b, "q %.4f 0 0 %.4f %.2f %.2f cm\n", scale, -scale, float64(pdfMargin), doc.height-pdfMargin)
+	if draw != nil {
+		draw(c)
+	}
+	c.b.WriteString("Q\n")
+
+	doc.pages = append(doc.pages, c.b.String())
+}
+
+// Bytes writes out the document in pdf format
+func (doc *PDFDocument) Bytes() []byte {
+	b := &bytes.Buffer{}
+	offsets := make([]int, 0)
+	object := func(body string) {
+		offsets = append(offsets, b.Len())
+		fmt.Fprintf(b, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// objects 1-4 are the catalog, page tree and fonts, then each page is followed by its content
+	kids := make([]string, 0)
+	for i := range doc.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(doc.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, content := range doc.pages {
+		compressed := &bytes.Buffer{}
+		w := zlib.NewWriter(compressed)
+		w.Write([]byte(content))
+		w.Close()
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %g %g] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", doc.width, doc.height, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.Bytes()))
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(b, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return b.Bytes()
+}
+
+// describeResult a one line summary of a game and its final score
+func describeResult(game Game) string {
+	linescore := CreateLinescore(game.Plays)
+	innings := ""
+	if len(linescore.Innings[0]) != 9 {
+		innings = fmt.Sprintf(" (%d)", len(linescore.Innings[0]))
+	}
+
+	return fmt.Sprintf("%s  %s %d, %s %d%s", game.Info.Date, game.Info.Visteam.Name, linescore.Runs[0], game.Info.Hometeam.Name, linescore.Runs[1], innings)
+}
+
+// CreateScorecardPDF Given a title and games, lay out a printable document of their scorecards labelled in the language of the catalog.
+// The document opens with an index of the games, then each game gets two facing pages, the visitor on the left and the home team on the right.
+func CreateScorecardPDF(title string, games []Game, size string, catalog Catalog) ([]byte, error) {
+	doc, err := NewPDFDocument(size)
+	if err != nil {
+		return nil, err
+	}
+	// the standard fonts only have the WinAnsi characters, which rules out languages like Japanese
+	if strings.ContainsRune(pdfEncode(catalog.Name), '?') {
+		return nil, fmt.Errorf("the pdf fonts can't set %s, try svg or html", catalog.Name)
+	}
+
+	sorted := make([]Game, len(games))
+	copy(sorted, games)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	lineWidth := doc.width - 2*pdfMargin
+	pageHeight := doc.height - 2*pdfMargin
+	for start := 0; start < len(sorted) || start == 0; start += pdfIndexLines {
+		end := start + pdfIndexLines
+		if end > len(sorted) {
+			end = len(sorted)
+		}
+		page := sorted[start:end]
+
+		doc.AddPage(lineWidth, pageHeight, func(c canvas) {
+			c.Text(0, 24, "start", 20, true, title)
+			for i, game := range page {
+				c.Text(0, float64(60+i*16), "start", 11, false, fmt.Sprintf("%3d.  %s", start+i+1, describeResult(game)))
+			}
+		})
+	}
+
+	// keep every visitor scorecard on a left hand (even) page
+	if len(doc.pages)%2 == 0 {
+		doc.AddPage(lineWidth, pageHeight, nil)
+	}
+
+	for i := range sorted {
+		game := &sorted[i]
+		linescore := CreateLinescore(game.Plays)
+
+		for team := 0; team < 2; team++ {
+			scorecard := CreateScorecard(*game, team)
+			width, height := scorecardSize(game, scorecard, linescore)
+			doc.AddPage(width, height, func(c canvas) {
+				drawScorecard(c, game, scorecard, linescore, catalog)
+			})
+		}
+	}
+
+	return doc.Bytes(), nil
+}
+
+// writeScorecardPDF lays out the games as a pdf and writes it to the given file
+func writeScorecardPDF(outputPath string, title string, games []Game, size string, catalog Catalog) error {
+	data, err := CreateScorecardPDF(title, games, size, catalog)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(outputPath, data, 0644)
+}
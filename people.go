@@ -0,0 +1,149 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Biography the extended details of a person from the biofile, dates are written yyyy-mm-dd and blank when not known
+type Biography struct {
+	ID        string `json:"id"`
+	Nickname  string `json:"nickname"`
+	BirthDate string `json:"birthDate"`
+	// Debut and FinalGame are the first and last games played
+	Debut     string `json:"debut"`
+	FinalGame string `json:"finalGame"`
+	Bats      string `json:"bats"`
+	Throws    string `json:"throws"`
+	// Roles is some of player, manager, coach and umpire, going by which debuts the biofile has for the person
+	Roles []string `json:"roles"`
+}
+
+// Biographies Reference data of the extended details of people, keyed by id and loaded from the biofile
+var Biographies = make(map[string]Biography)
+
+// accentFolds the accented letters found in player names and the plain letters they are searched by
+var accentFolds = map[rune]string{
+	'á': "a", 'à': "a", 'â': "a", 'ä': "a", 'ã': "a", 'å': "a",
+	'é': "e", 'è': "e", 'ê': "e", 'ë': "e",
+	'í': "i", 'ì': "i", 'î': "i", 'ï': "i",
+	'ó': "o", 'ò': "o", 'ô': "o", 'ö': "o", 'õ': "o", 'ø': "o",
+	'ú': "u", 'ù': "u", 'û': "u", 'ü': "u",
+	'ý': "y", 'ÿ': "y", 'ñ': "n", 'ç': "c", 'ß': "ss", 'æ': "ae", 'œ': "oe",
+}
+
+// foldName lowercases a name and strips its accents and punctuation so names can be compared the way people type them
+func foldName(name string) string {
+	b := strings.Builder{}
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case accentFolds[r] != "":
+			b.WriteString(accentFolds[r])
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-':
+			b.WriteRune(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// peopleKey an entry in the sorted name list used for prefix searches
+type peopleKey struct {
+	key string
+	id  string
+}
+
+// PeopleIndex finds people in the reference data by id or name
+type PeopleIndex struct {
+	// names holds a folded "first last" and "last first" for everyone, sorted
+	names []peopleKey
+}
+
+// CreatePeopleIndex Given the People reference data as it stands, build an index over everyone's names
+func CreatePeopleIndex() PeopleIndex {
+	index := PeopleIndex{names: make([]peopleKey, 0, 2*len(People))}
+
+	for id, person := range People {
+		first := foldName(person.FirstName)
+		last := foldName(person.LastName)
+		for _, key := range []string{first + " " + last, last + " " + first} {
+			index.names = append(index.names, peopleKey{key: strings.TrimSpace(key), id: id})
+		}
+		if nickname := foldName(Biographies[id].Nickname); nickname != "" && nickname != first {
+			index.names = append(index.names, peopleKey{key: nickname + " " + last, id: id})
+		}
+	}
+
+	sort.Slice(index.names, func(i, j int) bool {
+		if index.names[i].key == index.names[j].key {
+			return index.names[i].id < index.names[j].id
+		}
+		return index.names[i].key < index.names[j].key
+	})
+
+	return index
+}
+
+// Lookup Given an id, return the person and their biography
+func (index PeopleIndex) Lookup(id string) (Person, Biography, bool) {
+	person, ok := People[id]
+
+	return person, Biographies[id], ok
+}
+
+// matches collects the people whose keys pass the test, each once and in name order, stopping at limit when it is above 0
+func (index PeopleIndex) matches(keys []peopleKey, test func(key string) bool, limit int) []Person {
+	found := make([]Person, 0)
+	seen := make(map[string]bool)
+
+	for _, k := range keys {
+		if seen[k.id] || !test(k.key) {
+			continue
+		}
+		seen[k.id] = true
+		found = append(found, People[k.id])
+		if limit > 0 && len(found) == limit {
+			break
+		}
+	}
+
+	return found
+}
+
+// Search Given a query, return everyone whose name holds every word of it, ignoring case and accents
+func (index PeopleIndex) Search(query string, limit int) []Person {
+	words := strings.Fields(foldName(query))
+	if len(words) == 0 {
+		return []Person{}
+	}
+
+	return index.matches(index.names, func(key string) bool {
+		for _, word := range words {
+			if !strings.Contains(key, word) {
+				return false
+			}
+		}
+		return true
+	}, limit)
+}
+
+// Prefix Given the start of a name, first name first or last name first, return the people whose names begin with it for autocomplete
+func (index PeopleIndex) Prefix(prefix string, limit int) []Person {
+	prefix = foldName(prefix)
+	if prefix == "" {
+		return []Person{}
+	}
+
+	start := sort.Search(len(index.names), func(i int) bool {
+		return index.names[i].key >= prefix
+	})
+	end := start
+	for end < len(index.names) && strings.HasPrefix(index.names[end].key, prefix) {
+		end++
+	}
+
+	return index.matches(index.names[start:end], func(key string) bool { return true }, limit)
+}
@@ -17,6 +17,11 @@ type Player struct {
 	Name             string           `json:"name"`
 	BattingPosition  int              `json:"battingPosition"`
 	FieldingPosition FieldingPosition `json:"fieldingPosition"`
+	// FullName, Bats, Throws and PrimaryPosition come from the team's roster and are blank when there isn't one
+	FullName        string `json:"fullName"`
+	Bats            string `json:"bats"`
+	Throws          string `json:"throws"`
+	PrimaryPosition string `json:"primaryPosition"`
 }
 
 // Lineup The starting lineup for teams in a game
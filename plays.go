@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -21,12 +22,56 @@ type PlayConfig struct {
 	runnerAdvancements []RunnerAdvancement
 	code               string
 	description        string
+	// catalog is the language the description is written in
+	catalog Catalog
+}
+
+// Substitution a player entering the game in place of another
+type Substitution struct {
+	Team       int    `json:"team"`
+	Player     Player `json:"player"`
+	ReplacedID string `json:"replacedId"`
+	// Comments are the com records that follow the sub record
+	Comments []string `json:"comments,omitempty"`
+}
+
+// Play a play record along with the situation of the game before and after it
+type Play struct {
+	Inning        int            `json:"inning"`
+	Team          int            `json:"team"`
+	BatterID      string         `json:"batterId"`
+	PitcherID     string         `json:"pitcherId"`
+	Count         string         `json:"count"`
+	Pitches       string         `json:"pitches"`
+	Event         Event          `json:"event"`
+	Code          string         `json:"code"`
+	Description   string         `json:"description"`
+	Substitutions []Substitution `json:"substitutions,omitempty"`
+	// Adjustments are the badj, padj, ladj, radj and presadj records that come before the play, as written in the event file
+	Adjustments []string `json:"adjustments,omitempty"`
+	// Comments are the com records that follow the play record
+	Comments []string  `json:"comments,omitempty"`
+	Before   Situation `json:"before"`
+	After    Situation `json:"after"`
+	Runs     int       `json:"runs"`
+	RBI      int       `json:"rbi"`
+	Scorers  []string  `json:"scorers,omitempty"`
+	RE24     float64   `json:"re24"`
+	// WinProbabilityBefore and WinProbabilityAfter are the home team's chance of winning
+	WinProbabilityBefore float64 `json:"winProbabilityBefore"`
+	WinProbabilityAfter  float64 `json:"winProbabilityAfter"`
+	// WPA is the win probability added for the batting team
+	WPA           float64 `json:"wpa"`
+	LeverageIndex float64 `json:"leverageIndex"`
+
+	// problems are inconsistencies found replaying the play
+	problems []error
 }
 
 // PlayCreator is a factor for Plays
 type PlayCreator func(playConfig PlayConfig, matches []string) PlayConfig
 
-func createPlayConfig(playSource string) PlayConfig {
+func createPlayConfig(playSource string, catalog Catalog) PlayConfig {
 	// playSource is: S9/L9S.2-H;1-3
 	play := strings.Split(playSource, ".")     // ["S9/L9S", "2-H;1-3"]
 	batterEvent := strings.Split(play[0], "/") // ["S9", "L9S"]
@@ -34,6 +79,7 @@ func createPlayConfig(playSource string) PlayConfig {
 		source:    playSource,
 		basicPlay: batterEvent[0],  // S9
 		modifiers: batterEvent[1:], // ["L9S"]
+		catalog:   catalog,
 	}
 
 	if len(play) == 2 {
@@ -59,23 +105,14 @@ func createPlayConfig(playSource string) PlayConfig {
 }
 
 /*
-	Records with no docs:
-		6(1)
-		143
-		366(1)
-*/
-
-var u = make([]string, 0)
-
-/*
-	basic plays: K
-	modifiers: C BINT DP BF
-	modifier groups: C BINT,DP BF
+basic plays: K
+modifiers: C BINT DP BF
+modifier groups: C BINT,DP BF
 */
 var playMatchers = map[*regexp.Regexp]PlayCreator{
 	regexp.MustCompile("^K$"): func(playConfig PlayConfig, matches []string) PlayConfig {
 		playConfig.code = "K"
-		playConfig.description = fmt.Sprintf("%s %s", "strike out", TranslateModifiers(playConfig.modifiers))
+		playConfig.description = fmt.Sprintf("%s %s", playConfig.catalog.Text("strike out"), playConfig.catalog.DescribeModifiers(playConfig.modifiers))
 
 		return playConfig
 	},
@@ -88,14 +125,12 @@ var playMatchers = map[*regexp.Regexp]PlayCreator{
 		switch position := matches[0]; position {
 		case "7", "8", "9":
 			playConfig.code = "F" + position
-			playConfig.description = fmt.Sprintf("fly ball out %s", FieldingPositions[position].Code)
+			playConfig.description = playConfig.catalog.Sprintf("fly ball out %s", playConfig.catalog.Position(position).Code)
 		default:
-			if len(playConfig.modifiers) > 0 {
-				playConfig.code = position
-			}
+			playConfig.code = position
 		}
 
-		playConfig.description = fmt.Sprintf("%s %s", playConfig.description, TranslateModifiers(playConfig.modifiers))
+		playConfig.description = fmt.Sprintf("%s %s", playConfig.description, playConfig.catalog.DescribeModifiers(playConfig.modifiers))
 
 		return playConfig
 	},
@@ -106,7 +141,7 @@ var playMatchers = map[*regexp.Regexp]PlayCreator{
 	*/
 	regexp.MustCompile("^(\\d)(\\d)$"): func(playConfig PlayConfig, matches []string) PlayConfig {
 		playConfig.code = fmt.Sprintf("%s-%s", matches[0], matches[1])
-		playConfig.description = fmt.Sprintf("ground ball %s to %s", FieldingPositions[matches[0]].Code, FieldingPositions[matches[1]].Code)
+		playConfig.description = playConfig.catalog.Sprintf("ground ball %s to %s", playConfig.catalog.Position(matches[0]).Code, playConfig.catalog.Position(matches[1]).Code)
 
 		return playConfig
 	},
@@ -117,7 +152,7 @@ var playMatchers = map[*regexp.Regexp]PlayCreator{
 	*/
 	regexp.MustCompile("^NP$"): func(playConfig PlayConfig, matches []string) PlayConfig {
 		playConfig.code = "NP"
-		playConfig.description = "no play"
+		playConfig.description = playConfig.catalog.Text("no play")
 
 		return playConfig
 	},
@@ -128,7 +163,7 @@ var playMatchers = map[*regexp.Regexp]PlayCreator{
 	*/
 	regexp.MustCompile("^WP$"): func(playConfig PlayConfig, matches []string) PlayConfig {
 		playConfig.code = "WP"
-		playConfig.description = "wild pitch"
+		playConfig.description = playConfig.catalog.Text("wild pitch")
 
 		return playConfig
 	},
@@ -143,14 +178,14 @@ var playMatchers = map[*regexp.Regexp]PlayCreator{
 		if len(matches) > 1 {
 			bases := make([]string, 0)
 			for _, v := range matches {
-				bases = append(bases, Bases[v])
+				bases = append(bases, playConfig.catalog.Base(v))
 			}
 
 			playConfig.code = fmt.Sprintf("SB(%s)", strings.Join(matches, ","))
-			playConfig.description = fmt.Sprintf("stole %s", strings.Join(bases, ", "))
+			playConfig.description = playConfig.catalog.Sprintf("stole %s", strings.Join(bases, ", "))
 		} else {
 			playConfig.code = fmt.Sprintf("SB%s", matches[0])
-			playConfig.description = fmt.Sprintf("stole %s", Bases[matches[0]])
+			playConfig.description = playConfig.catalog.Sprintf("stole %s", playConfig.catalog.Base(matches[0]))
 		}
 
 		return playConfig
@@ -163,195 +198,416 @@ var playMatchers = map[*regexp.Regexp]PlayCreator{
 	regexp.MustCompile("^S(\\d)?$"): func(playConfig PlayConfig, matches []string) PlayConfig {
 		if matches[0] == "" {
 			playConfig.code = "S"
-			playConfig.description = "single"
+			playConfig.description = playConfig.catalog.Text("single")
 		} else {
 			playConfig.code = fmt.Sprintf("S%s", matches[0])
-			playConfig.description = fmt.Sprintf("single to %s", FieldingPositions[matches[0]].Code)
+			playConfig.description = playConfig.catalog.Sprintf("single to %s", playConfig.catalog.Position(matches[0]).Code)
+		}
+
+		return playConfig
+	},
+	/*
+		basic plays: K23 K+SB2 K+CS2(24) K+WP K+PB K+E2
+		modifiers: DP
+		modifier groups: DP
+	*/
+	regexp.MustCompile("^K(\\d*)\\+(.+)$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		secondary := describeSecondary(matches[1], playConfig.catalog)
+		playConfig.code = fmt.Sprintf("K+%s", secondary.code)
+		playConfig.description = playConfig.catalog.Sprintf("strike out, %s %s", secondary.description, playConfig.catalog.DescribeModifiers(playConfig.modifiers))
+
+		return playConfig
+	},
+	regexp.MustCompile("^K(\\d+)$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = "K"
+		playConfig.description = playConfig.catalog.Sprintf("strike out, put out by %s %s", playConfig.catalog.Position(matches[0][len(matches[0])-1:]).Code, playConfig.catalog.DescribeModifiers(playConfig.modifiers))
+
+		return playConfig
+	},
+	/*
+		basic plays: D D7 D8 D9 DGR
+		modifiers: F9L L78 G5L F8XD
+		modifier groups: L9L F7LD L78
+	*/
+	regexp.MustCompile("^D(\\d)?$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		if matches[0] == "" {
+			playConfig.code = "D"
+			playConfig.description = playConfig.catalog.Text("double")
+		} else {
+			playConfig.code = fmt.Sprintf("D%s", matches[0])
+			playConfig.description = playConfig.catalog.Sprintf("double to %s", playConfig.catalog.Position(matches[0]).Code)
+		}
+
+		return playConfig
+	},
+	regexp.MustCompile("^DGR$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = "DGR"
+		playConfig.description = playConfig.catalog.Text("ground rule double")
+
+		return playConfig
+	},
+	/*
+		basic plays: T T7 T8 T9
+		modifiers: F89 L9L
+		modifier groups: F89 L9L
+	*/
+	regexp.MustCompile("^T(\\d)?$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		if matches[0] == "" {
+			playConfig.code = "T"
+			playConfig.description = playConfig.catalog.Text("triple")
+		} else {
+			playConfig.code = fmt.Sprintf("T%s", matches[0])
+			playConfig.description = playConfig.catalog.Sprintf("triple to %s", playConfig.catalog.Position(matches[0]).Code)
+		}
+
+		return playConfig
+	},
+	/*
+		basic plays: HR H HR7 HR8 HR9
+		modifiers: F7 F78 L7 IPHR
+		modifier groups: F7 F78 L7 IPHR
+	*/
+	regexp.MustCompile("^HR?(\\d)?$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = "HR"
+		playConfig.description = playConfig.catalog.Sprintf("home run %s", playConfig.catalog.DescribeModifiers(playConfig.modifiers))
+
+		return playConfig
+	},
+	/*
+		basic plays: FC1 FC3 FC5 FC6
+		modifiers: G SH BG
+		modifier groups: G SH,BG
+	*/
+	regexp.MustCompile("^FC(\\d)?$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		if matches[0] == "" {
+			playConfig.code = "FC"
+			playConfig.description = playConfig.catalog.Text("fielder's choice")
+		} else {
+			playConfig.code = fmt.Sprintf("FC%s", matches[0])
+			playConfig.description = playConfig.catalog.Sprintf("fielder's choice to %s", playConfig.catalog.Position(matches[0]).Code)
 		}
 
 		return playConfig
 	},
-	// 	// regexp.MustCompile("^D(\\d)?$"): PlayMatchConfig{
-	// 	// 	shortDescription: "D%s",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		if matches[0] != "" {
-	// 	// 			return fmt.Sprintf("double to %s", FieldingPositions[matches[0]].Code)
-	// 	// 		}
-
-	// 	// 		return "double"
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^T(\\d)?$"): PlayMatchConfig{
-	// 	// 	shortDescription: "T%s",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		if matches[0] != "" {
-	// 	// 			return fmt.Sprintf("triple to %s", FieldingPositions[matches[0]].Code)
-	// 	// 		}
-
-	// 	// 		return "triple"
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^FC(\\d)?$"): PlayMatchConfig{
-	// 	// 	shortDescription: "FC%s",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		if matches[0] != "" {
-	// 	// 			return fmt.Sprintf("fielder's choice to %s", FieldingPositions[matches[0]].Code)
-	// 	// 		}
-
-	// 	// 		return "fielder's choice"
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^E(\\d)?$"): PlayMatchConfig{
-	// 	// 	shortDescription: "E%s",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		if matches[0] != "" {
-	// 	// 			return fmt.Sprintf("error by %s", FieldingPositions[matches[0]].Code)
-	// 	// 		}
-
-	// 	// 		return "error"
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^W$"): PlayMatchConfig{
-	// 	// 	shortDescription: "W",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return "walk"
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^HP$"): PlayMatchConfig{
-	// 	// 	shortDescription: "HP",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return "hit by pitch"
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^I|IW$"): PlayMatchConfig{
-	// 	// 	shortDescription: "IW",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return "intentional walk"
-	// 	// 	},
-	// 	// },
-	// 	// // The two caught stealing situations below have fielding scenarios after them  to note where the throw was from and to whom
-	// 	// // but I'm not interested in logging that
-	// 	// regexp.MustCompile("^CS(2|3|H)"): PlayMatchConfig{
-	// 	// 	shortDescription: "CS%s",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return fmt.Sprintf("caught stealing %s", baseMap[matches[0]])
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^POCS(2|3|H)"): PlayMatchConfig{
-	// 	// 	shortDescription: "POCS%s",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return fmt.Sprintf("picked off at %s (caught stealing)", baseMap[matches[0]])
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^PO(1|2|3)\\(\\d\\d\\)"): PlayMatchConfig{
-	// 	// 	shortDescription: "PO%s",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return fmt.Sprintf("picked off at %s", baseMap[matches[0]])
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^PO(1|2|3)\\(E(\\d)\\)"): PlayMatchConfig{
-	// 	// 	shortDescription: "PO%s-E%s",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return fmt.Sprintf("pick off attempt at %s, error by %s", baseMap[matches[0]], FieldingPositions[matches[1]].Code)
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^(\\d)(\\d)\\((\\d)\\)$"): PlayMatchConfig{
-	// 	// 	shortDescription: "DP%s%s(%s)",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return "supposedly some sort of double play"
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^(\\d)(\\d)(\\d)$"): PlayMatchConfig{
-	// 	// 	shortDescription: "DP-%s%s%s",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return fmt.Sprintf("double play? %s-%s-%s", FieldingPositions[matches[0]].Code, FieldingPositions[matches[1]].Code, FieldingPositions[matches[2]].Code)
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^(\\d)\\(B\\)(\\d)\\((\\d)\\)$"): PlayMatchConfig{
-	// 	// 	shortDescription: "LDP-%s-%s(%s)",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return fmt.Sprintf("lined into double play %s to %s at %s", FieldingPositions[matches[0]].Code, FieldingPositions[matches[1]].Code, baseMap[matches[2]])
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^(\\d)\\(\\d\\)(\\d)$"): PlayMatchConfig{
-	// 	// 	shortDescription: "UGDP-%s-%s",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return fmt.Sprintf("ground ball double play, unassisted %s to %s", FieldingPositions[matches[0]].Code, FieldingPositions[matches[1]].Code)
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^(\\d)(\\d)\\((\\d)\\)(\\d)$"): PlayMatchConfig{
-	// 	// 	shortDescription: "DP%s%s(%s)%s",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return fmt.Sprintf("%s%s%s DP, runner at %s was the initial out", matches[0], matches[1], matches[3], baseMap[matches[2]])
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^PB$"): PlayMatchConfig{
-	// 	// 	shortDescription: "PB",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return "passed ball"
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^H|HR$"): PlayMatchConfig{
-	// 	// 	shortDescription: "HR",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return "home run"
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^BK$"): PlayMatchConfig{
-	// 	// 	shortDescription: "BK",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return "balk"
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^FLE(\\d)$"): PlayMatchConfig{
-	// 	// 	shortDescription: "FLE%s",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return fmt.Sprintf("error by %s on foul fly ball", FieldingPositions[matches[0]].Code)
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^C$"): PlayMatchConfig{
-	// 	// 	shortDescription: "C",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return "interference"
-	// 	// 	},
-	// 	// },
-	// 	// regexp.MustCompile("^OA$"): PlayMatchConfig{
-	// 	// 	shortDescription: "A",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return "base runner advance"
-	// 	// 	},
-	// 	// },
-	// 	// // @TODO research all these matches below further
-	// 	// regexp.MustCompile("^366\\(1\\)$"): PlayMatchConfig{
-	// 	// 	shortDescription: "366(1)",
-	// 	// 	longDescription: func(matches []string) string {
-	// 	// 		return "UNKNOWN"
-	// 	// 	},
-	// 	// },
+	/*
+		basic plays: E1 E3 E4 E5 E6 E7 E8 E9
+		modifiers: G P F TH BG
+		modifier groups: G P F G,TH BG
+	*/
+	regexp.MustCompile("^E(\\d)$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = fmt.Sprintf("E%s", matches[0])
+		playConfig.description = playConfig.catalog.Sprintf("error by %s %s", playConfig.catalog.Position(matches[0]).Code, playConfig.catalog.DescribeModifiers(playConfig.modifiers))
+
+		return playConfig
+	},
+	/*
+		basic plays: 4E3 5E3 6E3 1E3
+		modifiers: G TH
+		modifier groups: G,TH
+	*/
+	regexp.MustCompile("^(\\d)E(\\d)$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = fmt.Sprintf("E%s", matches[1])
+		playConfig.description = playConfig.catalog.Sprintf("error by %s on throw from %s", playConfig.catalog.Position(matches[1]).Code, playConfig.catalog.Position(matches[0]).Code)
+
+		return playConfig
+	},
+	/*
+		basic plays: FLE2 FLE3 FLE5
+		modifiers: n/a
+		modifier groups: n/a
+	*/
+	regexp.MustCompile("^FLE(\\d)$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = fmt.Sprintf("FLE%s", matches[0])
+		playConfig.description = playConfig.catalog.Sprintf("error by %s on foul fly ball", playConfig.catalog.Position(matches[0]).Code)
+
+		return playConfig
+	},
+	/*
+		basic plays: W W+WP W+PB W+SB2
+		modifiers: n/a
+		modifier groups: n/a
+	*/
+	regexp.MustCompile("^W(?:\\+(.+))?$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = "W"
+		playConfig.description = playConfig.catalog.Text("walk")
+
+		if matches[0] != "" {
+			secondary := describeSecondary(matches[0], playConfig.catalog)
+			playConfig.code = fmt.Sprintf("W+%s", secondary.code)
+			playConfig.description = playConfig.catalog.Sprintf("walk, %s", secondary.description)
+		}
+
+		return playConfig
+	},
+	/*
+		basic plays: I IW
+		modifiers: n/a
+		modifier groups: n/a
+	*/
+	regexp.MustCompile("^IW?(?:\\+(.+))?$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = "IW"
+		playConfig.description = playConfig.catalog.Text("intentional walk")
+
+		if matches[0] != "" {
+			secondary := describeSecondary(matches[0], playConfig.catalog)
+			playConfig.code = fmt.Sprintf("IW+%s", secondary.code)
+			playConfig.description = playConfig.catalog.Sprintf("intentional walk, %s", secondary.description)
+		}
+
+		return playConfig
+	},
+	regexp.MustCompile("^HP$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = "HP"
+		playConfig.description = playConfig.catalog.Text("hit by pitch")
+
+		return playConfig
+	},
+	regexp.MustCompile("^C$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = "CI"
+		playConfig.description = playConfig.catalog.Sprintf("interference %s", playConfig.catalog.DescribeModifiers(playConfig.modifiers))
+
+		return playConfig
+	},
+	/*
+		basic plays: CS2(26) CS2(24) CS3(25) CSH(12) CS2(136)
+		modifiers: DP
+		modifier groups: DP
+	*/
+	regexp.MustCompile("^CS(2|3|H)(?:\\((.*)\\))?$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = fmt.Sprintf("CS%s", matches[0])
+		playConfig.description = playConfig.catalog.Sprintf("caught stealing %s", playConfig.catalog.Base(matches[0]))
+
+		return playConfig
+	},
+	/*
+		basic plays: POCS2(1361) POCS2(E1)
+		modifiers: n/a
+		modifier groups: n/a
+	*/
+	regexp.MustCompile("^POCS(2|3|H)(?:\\((.*)\\))?$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = fmt.Sprintf("POCS%s", matches[0])
+		playConfig.description = playConfig.catalog.Sprintf("picked off at %s (caught stealing)", playConfig.catalog.Base(matches[0]))
+
+		return playConfig
+	},
+	/*
+		basic plays: PO1(13) PO2(14) PO1(E1)
+		modifiers: n/a
+		modifier groups: n/a
+	*/
+	regexp.MustCompile("^PO(1|2|3)\\(E(\\d).*\\)$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = fmt.Sprintf("PO%s-E%s", matches[0], matches[1])
+		playConfig.description = playConfig.catalog.Sprintf("pick off attempt at %s, error by %s", playConfig.catalog.Base(matches[0]), playConfig.catalog.Position(matches[1]).Code)
+
+		return playConfig
+	},
+	regexp.MustCompile("^PO(1|2|3)\\((\\d+)\\)$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = fmt.Sprintf("PO%s", matches[0])
+		playConfig.description = playConfig.catalog.Sprintf("picked off at %s", playConfig.catalog.Base(matches[0]))
+
+		return playConfig
+	},
+	regexp.MustCompile("^PB$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = "PB"
+		playConfig.description = playConfig.catalog.Text("passed ball")
+
+		return playConfig
+	},
+	regexp.MustCompile("^BK$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = "BK"
+		playConfig.description = playConfig.catalog.Text("balk")
+
+		return playConfig
+	},
+	regexp.MustCompile("^DI$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = "DI"
+		playConfig.description = playConfig.catalog.Text("defensive indifference")
+
+		return playConfig
+	},
+	regexp.MustCompile("^OA$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		playConfig.code = "OA"
+		playConfig.description = playConfig.catalog.Text("base runner advance")
+
+		return playConfig
+	},
+	/*
+		basic plays: 143 163 543 131
+		modifiers: G BG SH
+		modifier groups: G BG,SH
+	*/
+	regexp.MustCompile("^(\\d{3,})$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		fielders := strings.Split(matches[0], "")
+		codes := make([]string, 0)
+		for _, fielder := range fielders {
+			codes = append(codes, playConfig.catalog.Position(fielder).Code)
+		}
+
+		playConfig.code = strings.Join(fielders, "-")
+		playConfig.description = playConfig.catalog.Sprintf("ground ball %s", strings.Join(codes, playConfig.catalog.Text(" to ")))
+
+		return playConfig
+	},
+	/*
+		basic plays: 64(1)3 54(1) 6(1) 4(B)3(1) 366(1) 52(3)3 1(B)16(2)
+		modifiers: GDP FO LDP G
+		modifier groups: GDP FO FO,G LDP
+	*/
+	regexp.MustCompile("^(\\d+(?:\\([B123]\\)\\d*)+)$"): func(playConfig PlayConfig, matches []string) PlayConfig {
+		segments := fieldingOutSegments.FindAllStringSubmatch(matches[0], -1)
+		fielders := make([]string, 0)
+		codes := make([]string, 0)
+		outs := 0
+		batterOut := false
+
+		for i, segment := range segments {
+			for _, fielder := range strings.Split(segment[1], "") {
+				// the same fielder can both catch a throw and start the next one, only list them once
+				if len(fielders) == 0 || fielders[len(fielders)-1] != fielder {
+					fielders = append(fielders, fielder)
+					codes = append(codes, playConfig.catalog.Position(fielder).Code)
+				}
+			}
+
+			if segment[2] != "" {
+				outs++
+			}
+			if segment[2] == "B" || (segment[2] == "" && i == len(segments)-1) {
+				batterOut = true
+			}
+		}
+		if batterOut && !strings.Contains(matches[0], "(B)") {
+			outs++
+		}
+
+		switch {
+		case !batterOut:
+			playConfig.code = fmt.Sprintf("FC %s", strings.Join(fielders, "-"))
+			playConfig.description = playConfig.catalog.Sprintf("force out %s", strings.Join(codes, playConfig.catalog.Text(" to ")))
+		case outs == 2:
+			playConfig.code = fmt.Sprintf("%s DP", strings.Join(fielders, "-"))
+			playConfig.description = playConfig.catalog.Sprintf("double play %s", strings.Join(codes, playConfig.catalog.Text(" to ")))
+		case outs >= 3:
+			playConfig.code = fmt.Sprintf("%s TP", strings.Join(fielders, "-"))
+			playConfig.description = playConfig.catalog.Sprintf("triple play %s", strings.Join(codes, playConfig.catalog.Text(" to ")))
+		default:
+			playConfig.code = strings.Join(fielders, "-")
+			playConfig.description = playConfig.catalog.Sprintf("ground ball %s", strings.Join(codes, playConfig.catalog.Text(" to ")))
+		}
+
+		playConfig.description = fmt.Sprintf("%s %s", playConfig.description, playConfig.catalog.DescribeModifiers(playConfig.modifiers))
+
+		return playConfig
+	},
+}
+
+// describeSecondary describes the play after a "+" on a strikeout or walk.
+// It is describePlay, but has to be wired up in init since describePlay depends on the matchers that use it.
+var describeSecondary func(playSource string, catalog Catalog) PlayConfig
+
+func init() {
+	describeSecondary = describePlay
 }
 
-func processPlays(plays []string) {
-	playRecords := GetRecords(plays)
-	fmt.Println("there are", len(playRecords), "plays")
+// describePlay runs the event of a play record through the play matchers to get its scorecard code and the description
+// in the language of the catalog
+func describePlay(playSource string, catalog Catalog) PlayConfig {
+	config := createPlayConfig(playSource, catalog)
 
-	for _, play := range playRecords {
-		config := createPlayConfig(play[6])
+	for r, v := range playMatchers {
+		playResult := r.FindStringSubmatch(config.basicPlay)
 
-		for r, v := range playMatchers {
-			playResult := r.FindStringSubmatch(config.basicPlay)
+		if len(playResult) > 0 {
+			return v(config, playResult[1:])
+		}
+	}
+
+	return config
+}
 
-			if len(playResult) > 0 {
-				fmt.Println(v(config, playResult[1:]))
-				break
+// CreatePlays Given the raw play and sub records of a game in order, replay them from the starting lineups and return the
+// plays, along with any substitutions and adjustments that come after the last of them
+func CreatePlays(source []string, lineup Lineup) ([]Play, []Substitution, []string) {
+	plays := make([]Play, 0)
+	state := NewGameState(lineup)
+	subs := make([]Substitution, 0)
+	adjustments := make([]string, 0)
+
+	for i, r := range GetRecords(source) {
+		switch r[0] {
+		case "sub":
+			sub := createSubstitution(r)
+			sub.ReplacedID = state.Substitute(sub)
+			subs = append(subs, sub)
+			continue
+		case "com":
+			if len(subs) > 0 {
+				subs[len(subs)-1].Comments = append(subs[len(subs)-1].Comments, r[1])
+			} else if len(plays) > 0 {
+				plays[len(plays)-1].Comments = append(plays[len(plays)-1].Comments, r[1])
 			}
+			continue
+		case "badj", "padj", "ladj", "radj", "presadj":
+			adjustments = append(adjustments, source[i])
+			continue
 		}
 
-		// if hasPlayConfig == false {
-		// 	panic(fmt.Sprintf("no play config found for %s", basicPlay))
-		// }
+		inning, _ := strconv.Atoi(r[1])
+		team, _ := strconv.Atoi(r[2])
+		config := describePlay(r[6], Catalogs["en"])
+		event, err := ParseEvent(r[6])
+		play := Play{
+			Inning:        inning,
+			Team:          team,
+			BatterID:      r[3],
+			Count:         r[4],
+			Pitches:       r[5],
+			Event:         event,
+			Code:          config.code,
+			Description:   strings.TrimSpace(config.description),
+			Substitutions: subs,
+		}
+		if len(adjustments) > 0 {
+			play.Adjustments = adjustments
+		}
+		subs = make([]Substitution, 0)
+		adjustments = make([]string, 0)
+
+		if err != nil {
+			play.problems = append(play.problems, err)
+		}
+
+		if err := state.Enter(inning, team); err != nil {
+			play.problems = append(play.problems, err)
+		}
+		play.Before = state.Situation
+		play.PitcherID = state.Pitcher()
+
+		runs, rbi, scorers, problems := state.Apply(inning, team, r[3], event)
+		play.After = state.Situation
+		play.Runs = runs
+		play.RBI = rbi
+		play.Scorers = scorers
+		play.problems = append(play.problems, problems...)
 
-		fmt.Println("-----")
+		plays = append(plays, play)
 	}
 
-	fmt.Println("unique values:", u)
+	return plays, subs, adjustments
+}
+
+// createSubstitution Given a raw sub record, return the Substitution it describes
+func createSubstitution(r []string) Substitution {
+	team, _ := strconv.Atoi(r[3])
+	battingPosition, _ := strconv.Atoi(r[4])
+
+	return Substitution{
+		Team: team,
+		Player: Player{
+			ID:               r[1],
+			Name:             r[2],
+			BattingPosition:  battingPosition,
+			FieldingPosition: FieldingPositions[r[5]],
+		},
+	}
 }
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultReference the reference data the 1986 Mets home games need, built in so the tool runs without any downloads.
+// Its CurrentNames.csv is only a partial approximation of Retrosheet's: the 1986 National League teams and their
+// earlier codes, with each era rounded out to whole years and no divisions.
+//
+//go:embed data/reference
+var defaultReference embed.FS
+
+// defaultReferencePath where the built in reference data lives inside defaultReference
+const defaultReferencePath string = "data/reference"
+
+func init() {
+	if err := LoadReference(defaultReference, defaultReferencePath); err != nil {
+		panic(fmt.Sprintf("Error loading built in reference data: %v", err))
+	}
+}
+
+// readReferenceRecords reads every record of a comma separated reference file, dropping blank lines
+func readReferenceRecords(r io.Reader) ([][]string, error) {
+	csvReader := csv.NewReader(r)
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	csvReader.TrimLeadingSpace = true
+
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) > 0 && len(records[0]) > 0 {
+		// some files are saved with a byte order mark
+		records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff")
+	}
+
+	return records, nil
+}
+
+// referenceColumns maps each header of a file to its column so loaders can cope with the column orders of different releases.
+// Each field lists the header names it has gone by, and a field whose header is missing maps to -1.
+func referenceColumns(header []string, fields map[string][]string) map[string]int {
+	columns := make(map[string]int)
+	for field, names := range fields {
+		columns[field] = -1
+		for i, h := range header {
+			if Contains(names, strings.ToUpper(strings.TrimSpace(h))) {
+				columns[field] = i
+				break
+			}
+		}
+	}
+
+	return columns
+}
+
+// referenceField the value of a field in a record, blank when the file doesn't have the field
+func referenceField(record []string, columns map[string]int, field string) string {
+	i := columns[field]
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+
+	return strings.TrimSpace(record[i])
+}
+
+// LoadTeams Given a Retrosheet TEAMYYYY file (code, league, city, nickname) and its season, add its teams to the Teams and TeamSeasons reference data
+func LoadTeams(season int, r io.Reader) error {
+	records, err := readReferenceRecords(r)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := TeamSeasons[season]; !ok {
+		TeamSeasons[season] = make(map[string]Team)
+	}
+
+	for _, record := range records {
+		if len(record) < 4 {
+			continue
+		}
+
+		id := strings.TrimSpace(record[0])
+		team := Team{
+			ID:     id,
+			Name:   strings.TrimSpace(record[2] + " " + record[3]),
+			City:   strings.TrimSpace(record[2]),
+			League: Leagues[strings.TrimSpace(record[1])],
+		}
+		TeamSeasons[season][id] = team
+		Teams[id] = team
+	}
+
+	return nil
+}
+
+// LoadFranchises Given Retrosheet's CurrentNames.csv, add the eras of each team code to the TeamHistory reference data.
+// Its columns are the current franchise code, the code at the time, league, division, location, nickname, alternate nickname,
+// the dates of the first and last games, city and state.
+func LoadFranchises(r io.Reader) error {
+	records, err := readReferenceRecords(r)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if len(record) < 10 {
+			continue
+		}
+
+		from := referenceDate(record[7])
+		if from == 0 {
+			// a header line or a team that never played
+			continue
+		}
+
+		id := strings.TrimSpace(record[1])
+		TeamHistory[id] = append(TeamHistory[id], TeamEra{
+			Team: Team{
+				ID:        id,
+				Name:      strings.TrimSpace(record[4] + " " + record[5]),
+				City:      strings.TrimSpace(record[9]),
+				League:    leagueByCode(strings.TrimSpace(record[2])),
+				Division:  strings.TrimSpace(record[3]),
+				Franchise: strings.TrimSpace(record[0]),
+			},
+			From: from,
+			To:   referenceDate(record[8]),
+		})
+	}
+
+	return nil
+}
+
+// LoadParks Given Retrosheet's ballparks.csv, add its parks to the Parks reference data
+func LoadParks(r io.Reader) error {
+	records, err := readReferenceRecords(r)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	columns := referenceColumns(records[0], map[string][]string{
+		"id":    {"PARKID", "PARK ID"},
+		"name":  {"NAME"},
+		"city":  {"CITY"},
+		"state": {"STATE"},
+	})
+	if columns["id"] < 0 {
+		return fmt.Errorf("ballparks file has no PARKID column")
+	}
+
+	for _, record := range records[1:] {
+		id := referenceField(record, columns, "id")
+		if id == "" {
+			continue
+		}
+
+		state := referenceField(record, columns, "state")
+		Parks[id] = Park{
+			ID:       id,
+			Name:     referenceField(record, columns, "name"),
+			City:     referenceField(record, columns, "city"),
+			State:    state,
+			TimeZone: StateTimeZones[state],
+		}
+	}
+
+	return nil
+}
+
+// LoadPeople Given a Retrosheet biofile, add everyone in it to the People and Biographies reference data.
+// Both the older PLAYERID,LAST,FIRST layout and the newer id,lastname,usename layout are understood.
+func LoadPeople(r io.Reader) error {
+	records, err := readReferenceRecords(r)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	columns := referenceColumns(records[0], map[string][]string{
+		"id":           {"PLAYERID", "ID"},
+		"last":         {"LAST", "LASTNAME"},
+		"first":        {"FIRST", "USENAME"},
+		"nickname":     {"NICKNAME"},
+		"birthdate":    {"BIRTHDATE"},
+		"debut":        {"PLAY.DEBUT", "PLAY DEBUT", "DEBUT_P"},
+		"finalgame":    {"PLAY.LASTGAME", "PLAY LASTGAME", "LAST_P"},
+		"managerdebut": {"MGR.DEBUT", "MGR DEBUT", "DEBUT_M"},
+		"coachdebut":   {"COACH.DEBUT", "COACH DEBUT", "DEBUT_C"},
+		"umpiredebut":  {"UMP.DEBUT", "UMP DEBUT", "DEBUT_U"},
+		"bats":         {"BATS"},
+		"throws":       {"THROWS"},
+	})
+	if columns["id"] < 0 {
+		return fmt.Errorf("biofile has no PLAYERID column")
+	}
+
+	for _, record := range records[1:] {
+		id := referenceField(record, columns, "id")
+		if id == "" {
+			continue
+		}
+
+		People[id] = Person{
+			ID:        id,
+			FirstName: referenceField(record, columns, "first"),
+			LastName:  referenceField(record, columns, "last"),
+		}
+
+		roles := make([]string, 0)
+		for _, role := range []struct{ name, debut string }{{"player", "debut"}, {"manager", "managerdebut"}, {"coach", "coachdebut"}, {"umpire", "umpiredebut"}} {
+			if referenceField(record, columns, role.debut) != "" {
+				roles = append(roles, role.name)
+			}
+		}
+		Biographies[id] = Biography{
+			ID:        id,
+			Nickname:  referenceField(record, columns, "nickname"),
+			BirthDate: isoDate(referenceField(record, columns, "birthdate")),
+			Debut:     isoDate(referenceField(record, columns, "debut")),
+			FinalGame: isoDate(referenceField(record, columns, "finalgame")),
+			Bats:      referenceField(record, columns, "bats"),
+			Throws:    referenceField(record, columns, "throws"),
+			Roles:     roles,
+		}
+	}
+
+	return nil
+}
+
+// LoadRoster Given a Retrosheet .ROS file (id, last, first, bats, throws, team, position) and its season, add the players to the Rosters reference data.
+// Players not already known are added to the People reference data too.
+func LoadRoster(season int, r io.Reader) error {
+	records, err := readReferenceRecords(r)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := Rosters[season]; !ok {
+		Rosters[season] = make(map[string]Roster)
+	}
+
+	for _, record := range records {
+		if len(record) < 7 {
+			continue
+		}
+
+		entry := RosterEntry{
+			ID:        strings.TrimSpace(record[0]),
+			LastName:  strings.TrimSpace(record[1]),
+			FirstName: strings.TrimSpace(record[2]),
+			Bats:      strings.TrimSpace(record[3]),
+			Throws:    strings.TrimSpace(record[4]),
+			Team:      strings.TrimSpace(record[5]),
+			Position:  strings.TrimSpace(record[6]),
+		}
+		if _, ok := Rosters[season][entry.Team]; !ok {
+			Rosters[season][entry.Team] = make(Roster)
+		}
+		Rosters[season][entry.Team][entry.ID] = entry
+
+		if _, ok := People[entry.ID]; !ok {
+			People[entry.ID] = Person{ID: entry.ID, FirstName: entry.FirstName, LastName: entry.LastName}
+		}
+	}
+
+	return nil
+}
+
+// fileSeason the season in the name of a reference file, the four digits before its extension as in NYN1986.ROS or TEAM1986
+func fileSeason(name string) (int, error) {
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	if len(name) < 4 {
+		return 0, fmt.Errorf("no season in the file name")
+	}
+
+	season, err := strconv.Atoi(name[len(name)-4:])
+	if err != nil {
+		return 0, fmt.Errorf("no season in the file name")
+	}
+
+	return season, nil
+}
+
+// referenceLoaders pairs each kind of reference file with its loader, in the order they are loaded so rosters only fill the gaps left by the biofile
+var referenceLoaders = []struct {
+	matches func(name string) bool
+	load    func(name string, r io.Reader) error
+}{
+	{
+		func(name string) bool { return len(name) == 8 && strings.HasPrefix(name, "TEAM") },
+		func(name string, r io.Reader) error {
+			season, err := fileSeason(name)
+			if err != nil {
+				return err
+			}
+			return LoadTeams(season, r)
+		},
+	},
+	{
+		func(name string) bool { return name == "CURRENTNAMES.CSV" },
+		func(name string, r io.Reader) error { return LoadFranchises(r) },
+	},
+	{
+		func(name string) bool { return name == "BALLPARKS.CSV" },
+		func(name string, r io.Reader) error { return LoadParks(r) },
+	},
+	{
+		func(name string) bool { return strings.HasPrefix(name, "BIOFILE") },
+		func(name string, r io.Reader) error { return LoadPeople(r) },
+	},
+	{
+		func(name string) bool { return strings.HasSuffix(name, ".ROS") },
+		func(name string, r io.Reader) error {
+			season, err := fileSeason(name)
+			if err != nil {
+				return err
+			}
+			return LoadRoster(season, r)
+		},
+	},
+}
+
+// LoadReference Given a directory, load every TEAMYYYY, CurrentNames.csv, ballparks.csv, biofile and .ROS file found in it.
+// Anything loaded replaces the built in data for the same id, and a directory without reference files is left alone.
+func LoadReference(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, loader := range referenceLoaders {
+		for _, name := range names {
+			if !loader.matches(strings.ToUpper(name)) {
+				continue
+			}
+
+			file, err := fsys.Open(filepath.ToSlash(filepath.Join(dir, name)))
+			if err != nil {
+				return err
+			}
+			err = loader.load(strings.ToUpper(name), file)
+			file.Close()
+			if err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadReferenceDir loads the reference files in a directory on disk, see LoadReference
+func LoadReferenceDir(dir string) error {
+	return LoadReference(os.DirFS(dir), ".")
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestLoadPeopleBioColumns(t *testing.T) {
+	for _, file := range []string{"testdata/biofile-players.csv", "testdata/biofile-people.csv"} {
+		f, err := os.Open(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = LoadPeople(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+	}
+
+	want := map[string]Biography{
+		"testp901": {ID: "testp901", Nickname: "Patches", BirthDate: "1964-11-16", Debut: "1984-04-07", FinalGame: "2000-10-01", Bats: "R", Throws: "R", Roles: []string{"player"}},
+		"testm901": {ID: "testm901", BirthDate: "1943-01-30", Debut: "1965-09-13", FinalGame: "1978-07-21", Bats: "R", Throws: "R", Roles: []string{"player", "manager", "coach"}},
+		"testu901": {ID: "testu901", BirthDate: "1950-04-02", Roles: []string{"umpire"}},
+		"testb901": {ID: "testb901", BirthDate: "1960-10-20", Debut: "1982-08-30", FinalGame: "1990-10-03", Bats: "B", Throws: "L", Roles: []string{"player"}},
+	}
+	for id, biography := range want {
+		if got := Biographies[id]; !reflect.DeepEqual(got, biography) {
+			t.Errorf("%s: want %+v, got %+v", id, biography, got)
+		}
+	}
+
+	if person := LookupPerson("testb901"); person.FullName() != "Bo Batter" {
+		t.Errorf("want Bo Batter, got %s", person.FullName())
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReplayUnit how far a Replay steps at a time
+type ReplayUnit int
+
+// The units a Replay can step by
+const (
+	ReplayPitch ReplayUnit = iota
+	ReplayPlay
+	ReplayHalfInning
+	ReplayInning
+)
+
+// replayPitchCodes the pitch sequence codes that are pitches, as opposed to pickoff throws and other markers
+const replayPitchCodes = "ABCFHIKLMOPQRSTUVXY"
+
+// ReplayPosition a point in a game: Pitch pitches into the play at index Play, before the play itself happens.
+// Play is the number of plays at the end of the game.
+type ReplayPosition struct {
+	Play  int `json:"play"`
+	Pitch int `json:"pitch"`
+}
+
+// ReplaySnapshot everything about a game at a point in it
+type ReplaySnapshot struct {
+	Position ReplayPosition `json:"position"`
+	GameState
+	BatterID  string `json:"batterId"`
+	PitcherID string `json:"pitcherId"`
+	// Balls and Strikes are the count on the batter, Pitches the pitch sequence that led to it
+	Balls     int       `json:"balls"`
+	Strikes   int       `json:"strikes"`
+	Pitches   string    `json:"pitches"`
+	Linescore Linescore `json:"linescore"`
+	Final     bool      `json:"final"`
+}
+
+// Replay steps through a game one pitch, play, half inning or inning at a time, backwards or forwards
+type Replay struct {
+	game *Game
+	// states holds the game state before each play, its substitutions already made, and after the last play at the end
+	states   []GameState
+	position ReplayPosition
+}
+
+// CreateReplay Given a game, return a Replay of it positioned before the first pitch
+func CreateReplay(game *Game) *Replay {
+	replay := &Replay{game: game}
+	state := NewGameState(game.Lineup)
+
+	for _, play := range game.Plays {
+		for _, sub := range play.Substitutions {
+			state.Substitute(sub)
+		}
+		state.Enter(play.Inning, play.Team)
+		replay.states = append(replay.states, state)
+		state.Apply(play.Inning, play.Team, play.BatterID, play.Event)
+	}
+	replay.states = append(replay.states, state)
+
+	return replay
+}
+
+// pitchEnds where each pitch of a play's pitch sequence ends, so the sequence up to the nth pitch is pitches[:ends[n-1]]
+func pitchEnds(pitches string) []int {
+	ends := make([]int, 0)
+	for i, r := range pitches {
+		if strings.ContainsRune(replayPitchCodes, r) {
+			ends = append(ends, i+1)
+		}
+	}
+
+	return ends
+}
+
+// pitchPrefix the part of a play's pitch sequence that was already thrown. A batter's plate appearance interrupted by
+// a play like a stolen base or a wild pick off is recorded again from the first pitch on the batter's next play.
+func (replay *Replay) pitchPrefix(play int) string {
+	if play == 0 || play >= len(replay.game.Plays) {
+		return ""
+	}
+	previous, current := replay.game.Plays[play-1], replay.game.Plays[play]
+	if previous.Event.IsPlateAppearance() || previous.BatterID != current.BatterID || previous.Inning != current.Inning ||
+		previous.Team != current.Team || !strings.HasPrefix(current.Pitches, previous.Pitches) {
+		return ""
+	}
+
+	return previous.Pitches
+}
+
+// pitchPositions how many positions a play has: one before each pitch not already thrown, the last pitch being the
+// play itself
+func (replay *Replay) pitchPositions(play int) int {
+	if play >= len(replay.game.Plays) {
+		return 1
+	}
+	thrown := len(pitchEnds(replay.pitchPrefix(play)))
+	if n := len(pitchEnds(replay.game.Plays[play].Pitches)) - thrown; n > 0 {
+		return n
+	}
+
+	return 1
+}
+
+// Position where the replay is
+func (replay *Replay) Position() ReplayPosition {
+	return replay.position
+}
+
+// Seek moves the replay to a position, returning an error when the game has no such point
+func (replay *Replay) Seek(position ReplayPosition) error {
+	if position.Play < 0 || position.Play > len(replay.game.Plays) {
+		return fmt.Errorf("play %d is outside the game, which has %d", position.Play, len(replay.game.Plays))
+	}
+	if position.Pitch < 0 || position.Pitch >= replay.pitchPositions(position.Play) {
+		return fmt.Errorf("pitch %d is outside play %d, which has %d before it's put in play", position.Pitch, position.Play, replay.pitchPositions(position.Play))
+	}
+
+	replay.position = position
+
+	return nil
+}
+
+// boundaries the plays that start each unit, the end of the game included
+func (replay *Replay) boundaries(unit ReplayUnit) []int {
+	plays := replay.game.Plays
+	starts := make([]int, 0)
+	for i, play := range plays {
+		switch {
+		case i == 0, unit == ReplayPlay, unit == ReplayPitch:
+			starts = append(starts, i)
+		case unit == ReplayHalfInning && (play.Inning != plays[i-1].Inning || play.Team != plays[i-1].Team):
+			starts = append(starts, i)
+		case unit == ReplayInning && play.Inning != plays[i-1].Inning:
+			starts = append(starts, i)
+		}
+	}
+
+	return append(starts, len(plays))
+}
+
+// Step moves the replay by a unit, forwards or backwards as given, returning false at the start or end of the game.
+// Stepping back by anything but a pitch lands on the start of the current unit when the replay is partway through it.
+func (replay *Replay) Step(unit ReplayUnit, forward bool) bool {
+	position := replay.position
+
+	if unit == ReplayPitch {
+		switch {
+		case forward && position.Pitch+1 < replay.pitchPositions(position.Play):
+			position.Pitch++
+		case forward && position.Play < len(replay.game.Plays):
+			position = ReplayPosition{Play: position.Play + 1}
+		case !forward && position.Pitch > 0:
+			position.Pitch--
+		case !forward && position.Play > 0:
+			position = ReplayPosition{Play: position.Play - 1, Pitch: replay.pitchPositions(position.Play-1) - 1}
+		default:
+			return false
+		}
+		replay.position = position
+		return true
+	}
+
+	starts := replay.boundaries(unit)
+	if forward {
+		for _, start := range starts {
+			if start > position.Play {
+				replay.position = ReplayPosition{Play: start}
+				return true
+			}
+		}
+		return false
+	}
+	for i := len(starts) - 1; i >= 0; i-- {
+		if starts[i] < position.Play || (starts[i] == position.Play && position.Pitch > 0) {
+			replay.position = ReplayPosition{Play: starts[i]}
+			return true
+		}
+	}
+
+	return false
+}
+
+// Snapshot everything about the game where the replay is
+func (replay *Replay) Snapshot() ReplaySnapshot {
+	position := replay.position
+	snapshot := ReplaySnapshot{
+		Position:  position,
+		GameState: replay.states[position.Play],
+		Linescore: CreateLinescore(replay.game.Plays[:position.Play]),
+		Final:     position.Play == len(replay.game.Plays),
+	}
+	if snapshot.Final {
+		return snapshot
+	}
+
+	play := replay.game.Plays[position.Play]
+	snapshot.BatterID = play.BatterID
+	snapshot.PitcherID = snapshot.GameState.Pitcher()
+	prefix := replay.pitchPrefix(position.Play)
+	snapshot.Pitches = prefix
+	if ends := pitchEnds(play.Pitches); position.Pitch > 0 {
+		snapshot.Pitches = play.Pitches[:ends[len(pitchEnds(prefix))+position.Pitch-1]]
+	}
+	snapshot.Balls, snapshot.Strikes = pitchCount(snapshot.Pitches)
+
+	return snapshot
+}
+
+// Find moves the replay forward a play at a time from where it is until the snapshot matches, for questions like
+// "what was the situation when the tying run came up". The replay is left where it was when nothing matches.
+func (replay *Replay) Find(match func(snapshot ReplaySnapshot) bool) (ReplaySnapshot, bool) {
+	start := replay.position
+	for {
+		if snapshot := replay.Snapshot(); match(snapshot) {
+			return snapshot, true
+		}
+		if !replay.Step(ReplayPlay, true) {
+			replay.position = start
+			return ReplaySnapshot{}, false
+		}
+	}
+}
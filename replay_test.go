@@ -0,0 +1,94 @@
+package main
+
+import "testing"
+
+// replayTestGame a first inning with a stolen base partway through a plate appearance and its pitches recorded again on
+// the batter's next play, then one play in the top of the second
+func replayTestGame(t *testing.T) *Game {
+	t.Helper()
+	games := CreateGames([]byte(gameWithPlays(t,
+		"play,1,0,colev001,11,BC1,SB2",
+		"play,1,0,colev001,22,BC1.BFX,63",
+		"play,1,0,mcgew001,00,X,8",
+		"play,1,0,herrt001,01,CX,43",
+		"play,1,1,dyksl001,02,CSS,K",
+		"play,1,1,teuft001,10,BX,8",
+		"play,1,1,hernk001,00,X,43",
+		"play,2,0,clarj001,00,X,9",
+	)))
+
+	return &games[0]
+}
+
+func TestReplaySeekPitches(t *testing.T) {
+	replay := CreateReplay(replayTestGame(t))
+
+	tests := []struct {
+		position ReplayPosition
+		pitches  string
+		balls    int
+		strikes  int
+	}{
+		{ReplayPosition{0, 0}, "", 0, 0},
+		{ReplayPosition{0, 1}, "B", 1, 0},
+		{ReplayPosition{1, 0}, "BC1", 1, 1},
+		{ReplayPosition{1, 1}, "BC1.B", 2, 1},
+		{ReplayPosition{1, 2}, "BC1.BF", 2, 2},
+		{ReplayPosition{2, 0}, "", 0, 0},
+		{ReplayPosition{4, 2}, "CS", 0, 2},
+	}
+	for _, test := range tests {
+		if err := replay.Seek(test.position); err != nil {
+			t.Fatalf("%+v: %v", test.position, err)
+		}
+		snapshot := replay.Snapshot()
+		if snapshot.Pitches != test.pitches || snapshot.Balls != test.balls || snapshot.Strikes != test.strikes {
+			t.Errorf("%+v: want %q %d-%d, got %q %d-%d", test.position, test.pitches, test.balls, test.strikes,
+				snapshot.Pitches, snapshot.Balls, snapshot.Strikes)
+		}
+	}
+
+	for _, position := range []ReplayPosition{{1, 3}, {0, 2}, {-1, 0}, {9, 0}, {8, 1}} {
+		if err := replay.Seek(position); err == nil {
+			t.Errorf("%+v: want an error", position)
+		}
+	}
+}
+
+func TestReplayStep(t *testing.T) {
+	tests := []struct {
+		unit    ReplayUnit
+		forward bool
+		from    ReplayPosition
+		want    []ReplayPosition
+		// stops is set when there's nowhere further to step after the last position
+		stops bool
+	}{
+		{ReplayPitch, true, ReplayPosition{0, 0}, []ReplayPosition{{0, 1}, {1, 0}, {1, 1}, {1, 2}, {2, 0}, {3, 0}, {3, 1}, {4, 0}}, false},
+		{ReplayPitch, true, ReplayPosition{7, 0}, []ReplayPosition{{8, 0}}, true},
+		{ReplayPitch, false, ReplayPosition{2, 0}, []ReplayPosition{{1, 2}, {1, 1}, {1, 0}, {0, 1}, {0, 0}}, true},
+		{ReplayPlay, true, ReplayPosition{0, 1}, []ReplayPosition{{1, 0}, {2, 0}, {3, 0}, {4, 0}}, false},
+		{ReplayPlay, false, ReplayPosition{1, 2}, []ReplayPosition{{1, 0}, {0, 0}}, true},
+		{ReplayHalfInning, true, ReplayPosition{1, 1}, []ReplayPosition{{4, 0}, {7, 0}, {8, 0}}, true},
+		{ReplayHalfInning, false, ReplayPosition{8, 0}, []ReplayPosition{{7, 0}, {4, 0}, {0, 0}}, true},
+		{ReplayHalfInning, false, ReplayPosition{5, 1}, []ReplayPosition{{4, 0}, {0, 0}}, true},
+		{ReplayInning, true, ReplayPosition{0, 0}, []ReplayPosition{{7, 0}, {8, 0}}, true},
+		{ReplayInning, false, ReplayPosition{6, 0}, []ReplayPosition{{0, 0}}, true},
+	}
+
+	for _, test := range tests {
+		replay := CreateReplay(replayTestGame(t))
+		if err := replay.Seek(test.from); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range test.want {
+			if !replay.Step(test.unit, test.forward) || replay.Position() != want {
+				t.Errorf("unit %d forward %v from %+v: want %+v, got %+v", test.unit, test.forward, test.from, want, replay.Position())
+				break
+			}
+		}
+		if test.stops && replay.Step(test.unit, test.forward) {
+			t.Errorf("unit %d forward %v from %+v: want to stop, got %+v", test.unit, test.forward, test.from, replay.Position())
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+)
+
+// RosterEntry a player on a team's roster for a season
+type RosterEntry struct {
+	ID        string `json:"id"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	// Bats is L, R or B for a switch hitter, Throws is L or R
+	Bats     string `json:"bats"`
+	Throws   string `json:"throws"`
+	Team     string `json:"team"`
+	Position string `json:"position"`
+}
+
+// FullName the first and last name of the player
+func (entry RosterEntry) FullName() string {
+	return strings.TrimSpace(entry.FirstName + " " + entry.LastName)
+}
+
+// Roster the players on a team for a season, keyed by player id
+type Roster map[string]RosterEntry
+
+// Rosters Reference data of every team's roster, keyed by season then team code and loaded from .ROS files
+var Rosters = make(map[int]map[string]Roster)
+
+// LookupRosterEntry Given a season, team code and player id, return the player's roster entry.
+// A player missing from the team's roster, say after a trade the rosters don't reflect, is looked for on the other teams that season.
+func LookupRosterEntry(season int, team string, id string) (RosterEntry, bool) {
+	if entry, ok := Rosters[season][team][id]; ok {
+		return entry, true
+	}
+
+	for _, roster := range Rosters[season] {
+		if entry, ok := roster[id]; ok {
+			return entry, true
+		}
+	}
+
+	return RosterEntry{}, false
+}
+
+// joinRoster fills in a player's full name, handedness and primary position from the roster
+func (player *Player) joinRoster(season int, team string) {
+	entry, ok := LookupRosterEntry(season, team, player.ID)
+	if !ok {
+		return
+	}
+
+	player.FullName = entry.FullName()
+	player.Bats = entry.Bats
+	player.Throws = entry.Throws
+	player.PrimaryPosition = entry.Position
+}
+
+// joinRosters fills in the roster details of everyone in a game's lineups and substitutions
+func (game *Game) joinRosters() {
+	season := game.Info.Date.Year()
+	teams := [2]string{game.Info.Visteam.ID, game.Info.Hometeam.ID}
+
+	for i := range game.Lineup.Visitor {
+		game.Lineup.Visitor[i].joinRoster(season, teams[0])
+	}
+	for i := range game.Lineup.Home {
+		game.Lineup.Home[i].joinRoster(season, teams[1])
+	}
+	for i := range game.Plays {
+		for j := range game.Plays[i].Substitutions {
+			sub := &game.Plays[i].Substitutions[j]
+			sub.Player.joinRoster(season, teams[sub.Team])
+		}
+	}
+	for i := range game.TrailingSubstitutions {
+		sub := &game.TrailingSubstitutions[i]
+		sub.Player.joinRoster(season, teams[sub.Team])
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RunExpectancy the average number of runs scored from each base-out state through the end of the half inning
+type RunExpectancy struct {
+	// Runs is indexed by the runners bit mask and then outs
+	Runs [8][3]float64 `json:"runs"`
+	// Occurrences is how many times each state was seen building the matrix
+	Occurrences [8][3]int `json:"occurrences"`
+}
+
+// PlayerRunValue the total run value of the plays a player was involved in
+type PlayerRunValue struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Plays int     `json:"plays"`
+	RE24  float64 `json:"re24"`
+}
+
+// Value the expected runs for a base-out state, with three outs worth nothing
+func (re *RunExpectancy) Value(state BaseOutState) float64 {
+	if state.Outs >= 3 {
+		return 0
+	}
+
+	return re.Runs[state.Runners][state.Outs]
+}
+
+// String formats the matrix as a table of runners by outs
+func (re *RunExpectancy) String() string {
+	lines := []string{"runners      0 outs  1 out   2 outs"}
+
+	for runners := 0; runners < 8; runners++ {
+		label := strings.Fields(BaseOutState{Runners: runners}.String())[0]
+		line := fmt.Sprintf("%-10s", label)
+		for outs := 0; outs < 3; outs++ {
+			line += fmt.Sprintf("%8.3f", re.Runs[runners][outs])
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// halfInnings splits the plays of a game into its half innings
+func halfInnings(plays []Play) [][]Play {
+	halves := make([][]Play, 0)
+
+	for i, play := range plays {
+		if i == 0 || play.Inning != plays[i-1].Inning || play.Team != plays[i-1].Team {
+			halves = append(halves, make([]Play, 0))
+		}
+		halves[len(halves)-1] = append(halves[len(halves)-1], play)
+	}
+
+	return halves
+}
+
+// BuildRunExpectancy Given a collection of games, return the run expectancy matrix they produce.
+// Half innings that don't reach three outs, like a walk-off, are left out since they were cut short.
+func BuildRunExpectancy(games []Game) RunExpectancy {
+	re := RunExpectancy{}
+	totals := [8][3]int{}
+
+	for _, game := range games {
+		for _, half := range halfInnings(game.Plays) {
+			if half[len(half)-1].After.Outs < 3 {
+				continue
+			}
+
+			remaining := 0
+			for _, play := range half {
+				remaining += play.Runs
+			}
+
+			for _, play := range half {
+				if play.Event.Type != EventNone {
+					state := play.Before.BaseOut()
+					re.Occurrences[state.Runners][state.Outs]++
+					totals[state.Runners][state.Outs] += remaining
+				}
+				remaining -= play.Runs
+			}
+		}
+	}
+
+	for runners := 0; runners < 8; runners++ {
+		for outs := 0; outs < 3; outs++ {
+			if re.Occurrences[runners][outs] > 0 {
+				re.Runs[runners][outs] = float64(totals[runners][outs]) / float64(re.Occurrences[runners][outs])
+			}
+		}
+	}
+
+	return re
+}
+
+// ApplyRunValues annotates every play of the games with its RE24 value:
+// the change in run expectancy plus the runs that scored on the play
+func (re *RunExpectancy) ApplyRunValues(games []Game) {
+	for g := range games {
+		for p := range games[g].Plays {
+			play := &games[g].Plays[p]
+			play.RE24 = re.Value(play.After.BaseOut()) - re.Value(play.Before.BaseOut()) + float64(play.Runs)
+		}
+	}
+}
+
+// AggregateRE24 totals the RE24 of annotated plays by batter and by pitcher, sorted best first.
+// Batters are credited with plate appearances only, pitchers with every play and from the defense's side so that runs saved are positive.
+func AggregateRE24(games []Game) ([]PlayerRunValue, []PlayerRunValue) {
+	batters := make(map[string]*PlayerRunValue)
+	pitchers := make(map[string]*PlayerRunValue)
+	credit := func(values map[string]*PlayerRunValue, id string, value float64) {
+		if _, ok := values[id]; !ok {
+			values[id] = &PlayerRunValue{ID: id, Name: LookupPerson(id).FullName()}
+		}
+		values[id].Plays++
+		values[id].RE24 += value
+	}
+
+	for _, game := range games {
+		for _, play := range game.Plays {
+			if play.Event.Type == EventNone {
+				continue
+			}
+			if play.Event.IsPlateAppearance() {
+				credit(batters, play.BatterID, play.RE24)
+			}
+			credit(pitchers, play.PitcherID, -play.RE24)
+		}
+	}
+
+	return sortRunValues(batters), sortRunValues(pitchers)
+}
+
+// sortRunValues flattens a map of run values into a slice ordered from highest to lowest
+func sortRunValues(values map[string]*PlayerRunValue) []PlayerRunValue {
+	list := make([]PlayerRunValue, 0)
+	for _, v := range values {
+		list = append(list, *v)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].RE24 == list[j].RE24 {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].RE24 > list[j].RE24
+	})
+
+	return list
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scoreHelp the commands the score prompt understands
+const scoreHelp = `Enter a play in Retrosheet notation, optionally followed by the count and pitches, e.g. S8/G.2-H 12 BCFX
+  sub <id> <team 0|1> <slot> <position> <name>   bring in a substitute, position by number or code (PH, PR, ...)
+  undo                                           take back the last play or substitution
+  card                                           show both scorecards, the linescore and box score
+  save [path]                                    write the game as an event file
+  help                                           show this list
+  quit                                           stop scoring`
+
+// Scorer keeps score of a game as it is played, prompting for each record
+type Scorer struct {
+	in      *bufio.Scanner
+	out     io.Writer
+	options TextOptions
+	// header holds the id, version, info and start records, records the play and sub records entered so far
+	header  []string
+	records []string
+	game    Game
+	// feed is where each play is published for anyone following the game live, nil when nobody is
+	feed *FeedHub
+}
+
+// CreateScorer Given where to read answers from and write prompts to, return a Scorer ready to set up a game
+func CreateScorer(in io.Reader, out io.Writer, options TextOptions) *Scorer {
+	return &Scorer{in: bufio.NewScanner(in), out: out, options: options}
+}
+
+// Broadcast publishes the game to a feed hub as it is scored, so it can be followed live
+func (scorer *Scorer) Broadcast(hub *FeedHub) {
+	scorer.feed = hub
+}
+
+// publish sends an event with where the game stands now to the feed, when there is one
+func (scorer *Scorer) publish(eventType string, update func(event *FeedEvent)) {
+	if scorer.feed == nil {
+		return
+	}
+
+	event := stateEvent(eventType, scorer.game, scorer.game.Plays)
+	if update != nil {
+		update(&event)
+	}
+	scorer.feed.Publish(event)
+}
+
+// ask prompts for an answer, giving back the default when the answer is blank. ok is false once the input runs out.
+func (scorer *Scorer) ask(question string, def string) (string, bool) {
+	if def != "" {
+		fmt.Fprintf(scorer.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(scorer.out, "%s: ", question)
+	}
+
+	if !scorer.in.Scan() {
+		return "", false
+	}
+	answer := strings.TrimSpace(scorer.in.Text())
+	if answer == "" {
+		answer = def
+	}
+
+	return answer, true
+}
+
+// parsePosition Given a fielding position by number or by code, such as 6 or SS, return its number
+func parsePosition(value string) (string, error) {
+	if _, ok := FieldingPositions[value]; ok {
+		return value, nil
+	}
+	for id, position := range FieldingPositions {
+		if strings.EqualFold(position.Code, value) {
+			return id, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown position '%s'", value)
+}
+
+// Setup prompts for the game information and both starting lineups, returning false if the input ran out first
+func (scorer *Scorer) Setup() bool {
+	answers := make(map[string]string)
+	for _, question := range []struct{ key, text, def string }{
+		{"visteam", "Visiting team code", ""},
+		{"hometeam", "Home team code", ""},
+		{"site", "Park id", ""},
+		{"date", "Date (yyyy/mm/dd)", time.Now().Format("2006/01/02")},
+		{"usedh", "Designated hitter (y/n)", "n"},
+	} {
+		answer, ok := scorer.ask(question.text, question.def)
+		if !ok {
+			return false
+		}
+		answers[question.key] = answer
+	}
+
+	usedh := strings.HasPrefix(strings.ToLower(answers["usedh"]), "y")
+	id := fmt.Sprintf("%s%s0", strings.ToUpper(answers["hometeam"]), strings.Replace(answers["date"], "/", "", -1))
+	scorer.header = []string{
+		"id," + id,
+		"version,2",
+		"info,visteam," + strings.ToUpper(answers["visteam"]),
+		"info,hometeam," + strings.ToUpper(answers["hometeam"]),
+		"info,site," + strings.ToUpper(answers["site"]),
+		"info,date," + answers["date"],
+		"info,number,0",
+		"info,usedh," + strconv.FormatBool(usedh),
+	}
+
+	slots := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	if usedh {
+		// the pitcher doesn't bat, and is listed in slot 0
+		slots = append(slots, 0)
+	}
+	for team, side := range []string{"Visitor", "Home"} {
+		fmt.Fprintf(scorer.out, "%s lineup, one player per slot as <id> <position> <name>\n", side)
+		for _, slot := range slots {
+			for {
+				label := fmt.Sprintf("  %d", slot)
+				if slot == 0 {
+					label = "  pitcher"
+				}
+				answer, ok := scorer.ask(label, "")
+				if !ok {
+					return false
+				}
+				fields := strings.Fields(answer)
+				if len(fields) < 3 {
+					fmt.Fprintln(scorer.out, "Enter the id, position and name")
+					continue
+				}
+				position, err := parsePosition(fields[1])
+				if err != nil {
+					fmt.Fprintln(scorer.out, err)
+					continue
+				}
+				scorer.header = append(scorer.header, fmt.Sprintf("start,%s,%s,%d,%d,%s", fields[0], eventFileQuote(strings.Join(fields[2:], " ")), team, slot, position))
+				break
+			}
+		}
+	}
+
+	scorer.rebuild()
+
+	return true
+}
+
+// rebuild reads the records entered so far back into a Game
+func (scorer *Scorer) rebuild() {
+	scorer.game = CreateGame(strings.Join(append(append([]string{}, scorer.header...), scorer.records...), "\r\n"))
+	if scorer.options.Catalog.Language != "" && scorer.options.Catalog.Language != "en" {
+		scorer.game.Localize(scorer.options.Catalog)
+	}
+}
+
+// pendingSubs the substitutions entered since the last play, which the game only picks up once the next play is entered
+func (scorer *Scorer) pendingSubs() []Substitution {
+	subs := make([]Substitution, 0)
+	for i := len(scorer.records) - 1; i >= 0 && strings.HasPrefix(scorer.records[i], "sub,"); i-- {
+		subs = append([]Substitution{createSubstitution(GetRecords([]string{scorer.records[i]})[0])}, subs...)
+	}
+
+	return subs
+}
+
+// Up the inning and team batting next along with the batter due up and the current situation
+func (scorer *Scorer) Up() (int, int, string, Situation) {
+	state := NewGameState(scorer.game.Lineup)
+	up := [2]int{1, 1}
+	inning, team := 1, 0
+	situation := Situation{}
+
+	slot := func(team int, id string) int {
+		for slot := 1; slot <= 9; slot++ {
+			if state.Lineups[team][slot] == id {
+				return slot
+			}
+		}
+		return up[team]
+	}
+
+	for _, play := range scorer.game.Plays {
+		for _, sub := range play.Substitutions {
+			state.Substitute(sub)
+		}
+		if play.Event.IsPlateAppearance() {
+			up[play.Team] = slot(play.Team, play.BatterID)%9 + 1
+		}
+		inning, team, situation = play.Inning, play.Team, play.After
+	}
+	for _, sub := range scorer.pendingSubs() {
+		state.Substitute(sub)
+	}
+
+	if situation.Outs >= 3 {
+		if team == 1 {
+			inning++
+		}
+		team = 1 - team
+		situation = Situation{Score: situation.Score}
+	}
+
+	return inning, team, state.Lineups[team][up[team]], situation
+}
+
+// Play adds a play record for the batter due up, undoing it when the event can't be read or doesn't fit the base-out state
+func (scorer *Scorer) Play(event string, count string, pitches string) error {
+	inning, team, batter, _ := scorer.Up()
+	scorer.records = append(scorer.records, fmt.Sprintf("play,%d,%d,%s,%s,%s,%s", inning, team, batter, count, pitches, event))
+	scorer.rebuild()
+
+	plays := scorer.game.Plays
+	if problems := plays[len(plays)-1].problems; len(problems) > 0 {
+		scorer.undo()
+		messages := make([]string, 0)
+		for _, problem := range problems {
+			messages = append(messages, problem.Error())
+		}
+		return fmt.Errorf("%s", strings.Join(messages, "; "))
+	}
+	scorer.publish("play", func(event *FeedEvent) {
+		event.Play = &plays[len(plays)-1]
+	})
+
+	return nil
+}
+
+// Sub adds a sub record
+func (scorer *Scorer) Sub(id string, team string, slot string, position string, name string) error {
+	if team != "0" && team != "1" {
+		return fmt.Errorf("team must be 0 for the visitor or 1 for the home team")
+	}
+	if n, err := strconv.Atoi(slot); err != nil || n < 0 || n > 9 {
+		return fmt.Errorf("slot must be 1 to 9, or 0 for a pitcher who doesn't bat")
+	}
+	position, err := parsePosition(position)
+	if err != nil {
+		return err
+	}
+
+	scorer.records = append(scorer.records, fmt.Sprintf("sub,%s,%s,%s,%s,%s", id, eventFileQuote(name), team, slot, position))
+	scorer.rebuild()
+	subs := scorer.pendingSubs()
+	scorer.publish("substitution", func(event *FeedEvent) {
+		event.Substitution = &subs[len(subs)-1]
+	})
+
+	return nil
+}
+
+// Undo takes back the last play or sub record, returning false when there is nothing to take back
+func (scorer *Scorer) Undo() bool {
+	if !scorer.undo() {
+		return false
+	}
+	scorer.publish("undo", nil)
+
+	return true
+}
+
+// undo takes back the last record without telling the feed, for plays that never made it in
+func (scorer *Scorer) undo() bool {
+	if len(scorer.records) == 0 {
+		return false
+	}
+
+	scorer.records = scorer.records[:len(scorer.records)-1]
+	scorer.rebuild()
+
+	return true
+}
+
+// EventFile the game so far as an event file, substitutions waiting on the next play included
+func (scorer *Scorer) EventFile() string {
+	return strings.Join(append(append([]string{}, scorer.header...), scorer.records...), "\r\n") + "\r\n"
+}
+
+// status describes where the game stands and who is up
+func (scorer *Scorer) status() string {
+	inning, team, batter, situation := scorer.Up()
+	names := playerNames(scorer.game)
+	for _, sub := range scorer.pendingSubs() {
+		names[sub.Player.ID] = sub.Player.Name
+	}
+
+	catalog := scorer.options.Catalog
+	return catalog.Sprintf("%s %d: %s. %s up", catalog.Text([]string{"Top", "Bottom"}[team]), inning, describeSituation(situation, names, catalog), names[batter])
+}
+
+// Run reads plays and commands until the input runs out or quit is entered, telling the feed when the game starts and ends
+func (scorer *Scorer) Run() {
+	fmt.Fprintln(scorer.out, scoreHelp)
+	scorer.publish("start", nil)
+	defer scorer.publish("end", nil)
+
+	for {
+		fmt.Fprintln(scorer.out, scorer.status())
+		answer, ok := scorer.ask(">", "")
+		if !ok {
+			return
+		}
+		fields := strings.Fields(answer)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case "quit", "exit":
+			return
+		case "help":
+			fmt.Fprintln(scorer.out, scoreHelp)
+		case "undo":
+			if !scorer.Undo() {
+				fmt.Fprintln(scorer.out, "Nothing to undo")
+			}
+		case "card":
+			fmt.Fprintln(scorer.out, scorer.game.toText(scorer.options))
+		case "save":
+			path := fmt.Sprintf("%s/%s.EVN", outputPath, scorer.game.ID)
+			if len(fields) > 1 {
+				path = fields[1]
+			}
+			if err := ioutil.WriteFile(path, []byte(scorer.EventFile()), 0644); err != nil {
+				fmt.Fprintln(scorer.out, "Error saving game", err)
+			} else {
+				fmt.Fprintln(scorer.out, "Saved", path)
+			}
+		case "sub":
+			if len(fields) < 6 {
+				fmt.Fprintln(scorer.out, "Usage: sub <id> <team 0|1> <slot> <position> <name>")
+				continue
+			}
+			if err := scorer.Sub(fields[1], fields[2], fields[3], fields[4], strings.Join(fields[5:], " ")); err != nil {
+				fmt.Fprintln(scorer.out, err)
+			}
+		default:
+			count, pitches := "", ""
+			if len(fields) > 1 {
+				count = fields[1]
+			}
+			if len(fields) > 2 {
+				pitches = fields[2]
+			}
+			if err := scorer.Play(fields[0], count, pitches); err != nil {
+				fmt.Fprintln(scorer.out, "Play not recorded:", err)
+				continue
+			}
+
+			plays := scorer.game.Plays
+			fmt.Fprintln(scorer.out, plays[len(plays)-1].Description)
+			fmt.Fprintln(scorer.out, strings.Join(textScorecard(CreateScorecard(scorer.game, plays[len(plays)-1].Team), scorer.options), "\n"))
+		}
+	}
+}
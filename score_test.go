@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestScorerPlaySubUndo(t *testing.T) {
+	scorer := CreateScorer(strings.NewReader(""), ioutil.Discard, TextOptions{})
+	scorer.header = strings.Split(strings.TrimSuffix(gameWithPlays(t), "\r\n"), "\r\n")
+	scorer.rebuild()
+
+	// each step is a command as it would be typed at the prompt, followed by who is up and the outs and runners after it
+	tests := []struct {
+		command string
+		fails   bool
+		batter  string
+		outs    int
+		first   string
+	}{
+		{"K", false, "mcgew001", 1, ""},
+		{"S7", false, "herrt001", 1, "mcgew001"},
+		{"XX", true, "herrt001", 1, "mcgew001"},
+		{"SB3", true, "herrt001", 1, "mcgew001"},
+		{"sub orosj001 1 9 ZZ Jesse Orosco", true, "herrt001", 1, "mcgew001"},
+		{"sub orosj001 2 9 1 Jesse Orosco", true, "herrt001", 1, "mcgew001"},
+		{"sub orosj001 1 10 1 Jesse Orosco", true, "herrt001", 1, "mcgew001"},
+		{"sub hitte901 0 3 PH Pinch Hitter", false, "hitte901", 1, "mcgew001"},
+		{"undo", false, "herrt001", 1, "mcgew001"},
+		{"SB2", false, "herrt001", 1, ""},
+		{"undo", false, "herrt001", 1, "mcgew001"},
+		{"undo", false, "mcgew001", 1, ""},
+		{"undo", false, "colev001", 0, ""},
+		{"undo", true, "colev001", 0, ""},
+	}
+
+	for i, test := range tests {
+		fields := strings.Fields(test.command)
+		var err error
+		switch fields[0] {
+		case "sub":
+			err = scorer.Sub(fields[1], fields[2], fields[3], fields[4], strings.Join(fields[5:], " "))
+		case "undo":
+			if !scorer.Undo() {
+				err = fmt.Errorf("nothing to undo")
+			}
+		default:
+			err = scorer.Play(fields[0], "??", "")
+		}
+		if (err != nil) != test.fails {
+			t.Fatalf("step %d %s: want failure %v, got %v", i, test.command, test.fails, err)
+		}
+
+		_, _, batter, situation := scorer.Up()
+		if batter != test.batter || situation.Outs != test.outs || situation.Bases[0] != test.first {
+			t.Errorf("step %d %s: want %s up with %d out and %q on first, got %s with %d and %q", i, test.command,
+				test.batter, test.outs, test.first, batter, situation.Outs, situation.Bases[0])
+		}
+	}
+}
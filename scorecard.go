@@ -0,0 +1,252 @@
+package main
+
+import (
+	"fmt"
+)
+
+// ScorecardPlayer a player who appeared on a scorecard and the inning they entered, 0 for starters
+type ScorecardPlayer struct {
+	Player Player `json:"player"`
+	Inning int    `json:"inning"`
+}
+
+// ScorecardBox one plate appearance on a scorecard and what became of the batter after it
+type ScorecardBox struct {
+	Inning int `json:"inning"`
+	Column int `json:"column"`
+	// Play is the index of the plate appearance in the game's plays
+	Play     int    `json:"play"`
+	BatterID string `json:"batterId"`
+	Code     string `json:"code"`
+	Hit      bool   `json:"hit"`
+	// Bases is the furthest base the batter reached safely, 4 when they scored
+	Bases int `json:"bases"`
+	// Out is which out of the inning the batter made, 0 if they were never put out
+	Out int `json:"out"`
+	RBI int `json:"rbi"`
+	// Notes are the codes of the plays that moved the batter around the bases after reaching
+	Notes      []string `json:"notes,omitempty"`
+	LeftOnBase bool     `json:"leftOnBase"`
+}
+
+// ScorecardSlot a spot in the batting order with everyone who filled it and their plate appearances
+type ScorecardSlot struct {
+	Players []ScorecardPlayer `json:"players"`
+	Boxes   []ScorecardBox    `json:"boxes"`
+}
+
+// ScorecardInning the runs and hits a team had in an inning
+type ScorecardInning struct {
+	Inning int `json:"inning"`
+	Runs   int `json:"runs"`
+	Hits   int `json:"hits"`
+}
+
+// Scorecard one team's plate appearances laid out by batting order slot down and inning across
+type Scorecard struct {
+	Team int    `json:"team"`
+	Name string `json:"name"`
+	// Columns holds the inning of each column, an inning where the team batted around gets more than one
+	Columns  []int             `json:"columns"`
+	Slots    [9]ScorecardSlot  `json:"slots"`
+	Pitchers []ScorecardPlayer `json:"pitchers"`
+	Innings  []ScorecardInning `json:"innings"`
+}
+
+// boxRef locates a box in a scorecard while the runner it belongs to is on base
+type boxRef struct {
+	slot  int
+	index int
+}
+
+// CreateScorecard Given a game and a team (0 visitor, 1 home), lay out the team's scorecard
+func CreateScorecard(game Game, team int) Scorecard {
+	scorecard := Scorecard{
+		Team:     team,
+		Name:     game.Info.Visteam.Name,
+		Pitchers: make([]ScorecardPlayer, 0),
+		Innings:  make([]ScorecardInning, 0),
+	}
+	starters := game.Lineup.Visitor
+	if team == 1 {
+		scorecard.Name = game.Info.Hometeam.Name
+		starters = game.Lineup.Home
+	}
+
+	slots := make(map[string]int)
+	enter := func(player Player, inning int) {
+		if player.BattingPosition >= 1 && player.BattingPosition <= 9 {
+			slot := &scorecard.Slots[player.BattingPosition-1]
+			slots[player.ID] = player.BattingPosition - 1
+			// a player moving to a new position in the field stays in the same slot
+			if len(slot.Players) == 0 || slot.Players[len(slot.Players)-1].Player.ID != player.ID {
+				slot.Players = append(slot.Players, ScorecardPlayer{Player: player, Inning: inning})
+			}
+		}
+		if player.FieldingPosition.ID == "1" {
+			scorecard.Pitchers = append(scorecard.Pitchers, ScorecardPlayer{Player: player, Inning: inning})
+		}
+	}
+
+	for _, player := range starters {
+		enter(player, 0)
+	}
+
+	// position of each box within its slot and inning, turned into columns once all the boxes are known
+	orders := make(map[boxRef]int)
+	active := make(map[string]boxRef)
+	box := func(ref boxRef) *ScorecardBox {
+		return &scorecard.Slots[ref.slot].Boxes[ref.index]
+	}
+
+	for i, play := range game.Plays {
+		for _, sub := range play.Substitutions {
+			if sub.Team != team {
+				continue
+			}
+			enter(sub.Player, play.Inning)
+			if ref, ok := active[sub.ReplacedID]; ok && sub.Player.FieldingPosition.ID == "12" {
+				delete(active, sub.ReplacedID)
+				active[sub.Player.ID] = ref
+			}
+		}
+
+		if play.Team != team {
+			continue
+		}
+
+		if len(scorecard.Innings) == 0 || scorecard.Innings[len(scorecard.Innings)-1].Inning != play.Inning {
+			for _, ref := range active {
+				box(ref).LeftOnBase = true
+			}
+			active = make(map[string]boxRef)
+			scorecard.Innings = append(scorecard.Innings, ScorecardInning{Inning: play.Inning})
+		}
+		inning := &scorecard.Innings[len(scorecard.Innings)-1]
+		inning.Runs += play.Runs
+		if play.Event.HitValue() > 0 {
+			inning.Hits++
+		}
+
+		out := play.Before.Outs
+		note := play.Code
+		if play.Event.IsPlateAppearance() {
+			note = fmt.Sprintf("#%d", slots[play.BatterID]+1)
+		}
+
+		for _, runner := range play.Before.Bases {
+			ref, ok := active[runner]
+			if !ok {
+				continue
+			}
+
+			bases, safe := basesAfter(play, runner)
+			if !safe {
+				out++
+				box(ref).Out = out
+				delete(active, runner)
+				continue
+			}
+			if bases > box(ref).Bases {
+				box(ref).Bases = bases
+				box(ref).Notes = append(box(ref).Notes, note)
+			}
+			if bases == Home {
+				delete(active, runner)
+			}
+		}
+
+		if !play.Event.IsPlateAppearance() {
+			continue
+		}
+
+		slot, ok := slots[play.BatterID]
+		if !ok {
+			continue
+		}
+		ref := boxRef{slot: slot, index: len(scorecard.Slots[slot].Boxes)}
+		order := 0
+		for _, previous := range scorecard.Slots[slot].Boxes {
+			if previous.Inning == play.Inning {
+				order++
+			}
+		}
+		orders[ref] = order
+
+		scorecard.Slots[slot].Boxes = append(scorecard.Slots[slot].Boxes, ScorecardBox{
+			Inning:   play.Inning,
+			Play:     i,
+			BatterID: play.BatterID,
+			Code:     play.Code,
+			Hit:      play.Event.HitValue() > 0,
+			RBI:      play.RBI,
+		})
+
+		bases, safe := basesAfter(play, play.BatterID)
+		switch {
+		case !safe:
+			out++
+			box(ref).Out = out
+		case bases == Home:
+			box(ref).Bases = bases
+		default:
+			box(ref).Bases = bases
+			active[play.BatterID] = ref
+		}
+	}
+
+	for _, ref := range active {
+		box(ref).LeftOnBase = true
+	}
+
+	scorecard.layoutColumns(orders)
+
+	return scorecard
+}
+
+// basesAfter where a runner or batter ended up after a play and whether they were still safe
+func basesAfter(play Play, runner string) (int, bool) {
+	for i, id := range play.After.Bases {
+		if id == runner {
+			return i + 1, true
+		}
+	}
+
+	for _, id := range play.Scorers {
+		if id == runner {
+			return Home, true
+		}
+	}
+
+	return 0, false
+}
+
+// layoutColumns gives every inning at least one column, and as many as the most trips to the plate any slot made in it
+func (scorecard *Scorecard) layoutColumns(orders map[boxRef]int) {
+	lastInning := 9
+	widths := make(map[int]int)
+
+	for ref, order := range orders {
+		inning := scorecard.Slots[ref.slot].Boxes[ref.index].Inning
+		if order+1 > widths[inning] {
+			widths[inning] = order + 1
+		}
+		if inning > lastInning {
+			lastInning = inning
+		}
+	}
+
+	starts := make(map[int]int)
+	scorecard.Columns = make([]int, 0)
+	for inning := 1; inning <= lastInning; inning++ {
+		starts[inning] = len(scorecard.Columns)
+		for i := 0; i < widths[inning] || i == 0; i++ {
+			scorecard.Columns = append(scorecard.Columns, inning)
+		}
+	}
+
+	for ref, order := range orders {
+		box := &scorecard.Slots[ref.slot].Boxes[ref.index]
+		box.Column = starts[box.Inning] + order
+	}
+}
@@ -0,0 +1,457 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiDefaultLimit and apiMaxLimit how many results a page holds when the limit isn't given, and at most
+const (
+	apiDefaultLimit = 50
+	apiMaxLimit     = 500
+)
+
+// GameSummary the headline facts of a game, as lists of games give them
+type GameSummary struct {
+	ID      string `json:"id"`
+	Date    Date   `json:"date"`
+	Number  int    `json:"number"`
+	Site    Park   `json:"site"`
+	Visitor Team   `json:"visitor"`
+	Home    Team   `json:"home"`
+	Runs    [2]int `json:"runs"`
+	Hits    [2]int `json:"hits"`
+	Errors  [2]int `json:"errors"`
+	Innings int    `json:"innings"`
+	Result  string `json:"result"`
+}
+
+// GameList a page of games
+type GameList struct {
+	Total  int           `json:"total"`
+	Offset int           `json:"offset"`
+	Limit  int           `json:"limit"`
+	Games  []GameSummary `json:"games"`
+}
+
+// GameLogEntry what a player did in one game, Batting or Pitching left out when they didn't bat or pitch
+type GameLogEntry struct {
+	Game     GameSummary   `json:"game"`
+	Team     string        `json:"team"`
+	Opponent string        `json:"opponent"`
+	Home     bool          `json:"home"`
+	Batting  *BattingLine  `json:"batting,omitempty"`
+	Pitching *PitchingLine `json:"pitching,omitempty"`
+}
+
+// GameLog a page of the games a player appeared in
+type GameLog struct {
+	Player Person         `json:"player"`
+	Total  int            `json:"total"`
+	Offset int            `json:"offset"`
+	Limit  int            `json:"limit"`
+	Games  []GameLogEntry `json:"games"`
+}
+
+// TeamGame a game from one team's side, with the runs for and against and W or L
+type TeamGame struct {
+	Game         GameSummary `json:"game"`
+	Opponent     string      `json:"opponent"`
+	Home         bool        `json:"home"`
+	Runs         int         `json:"runs"`
+	OpponentRuns int         `json:"opponentRuns"`
+	Decision     string      `json:"decision"`
+}
+
+// TeamSeason a team's record over the games of a season in the corpus
+type TeamSeason struct {
+	Team        Team       `json:"team"`
+	Year        int        `json:"year"`
+	Wins        int        `json:"wins"`
+	Losses      int        `json:"losses"`
+	Ties        int        `json:"ties"`
+	RunsScored  int        `json:"runsScored"`
+	RunsAllowed int        `json:"runsAllowed"`
+	Games       []TeamGame `json:"games"`
+}
+
+// APIServer serves the parsed games as JSON over HTTP
+type APIServer struct {
+	games     []Game
+	summaries []GameSummary
+	boxScores []BoxScore
+	// byID indexes games by id, players the games each player appeared in
+	byID    map[string]int
+	players map[string][]int
+	// feed carries the games being scored live, nil when there aren't any
+	feed *FeedHub
+}
+
+// CreateGameSummary Given a game and its box score, return the summary lists of games give
+func CreateGameSummary(game Game, box BoxScore) GameSummary {
+	return GameSummary{
+		ID:      game.ID,
+		Date:    game.Info.Date,
+		Number:  gameNumber(game.Info.GameType),
+		Site:    game.Info.Site,
+		Visitor: game.Info.Visteam,
+		Home:    game.Info.Hometeam,
+		Runs:    box.Linescore.Runs,
+		Hits:    box.Linescore.Hits,
+		Errors:  box.Linescore.Errors,
+		Innings: len(box.Linescore.Innings[0]),
+		Result:  describeResult(game),
+	}
+}
+
+// CreateAPIServer Given the games to serve and the hub of any being scored live, index them by date, id and player.
+// Box scores are worked out up front so requests only have to look things up.
+func CreateAPIServer(games []Game, feed *FeedHub) *APIServer {
+	sorted := make([]Game, len(games))
+	copy(sorted, games)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].Info.Date.Equal(sorted[j].Info.Date.Time) {
+			return sorted[i].Info.Date.Before(sorted[j].Info.Date.Time)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	server := &APIServer{
+		games:   sorted,
+		byID:    make(map[string]int),
+		players: make(map[string][]int),
+		feed:    feed,
+	}
+	for i, game := range sorted {
+		box := CreateBoxScore(game)
+		server.boxScores = append(server.boxScores, box)
+		server.summaries = append(server.summaries, CreateGameSummary(game, box))
+		server.byID[game.ID] = i
+
+		appeared := make(map[string]bool)
+		for team := 0; team < 2; team++ {
+			for _, line := range box.Batting[team] {
+				appeared[line.ID] = true
+			}
+			for _, line := range box.Pitching[team] {
+				appeared[line.ID] = true
+			}
+		}
+		for id := range appeared {
+			server.players[id] = append(server.players[id], i)
+		}
+	}
+
+	return server
+}
+
+// writeJSON sends a value as the JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeError sends an error as a JSON response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// pageParams reads the limit and offset query parameters
+func pageParams(r *http.Request) (int, int, error) {
+	limit, offset := apiDefaultLimit, 0
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > apiMaxLimit {
+			return 0, 0, fmt.Errorf("limit must be 1 to %d", apiMaxLimit)
+		}
+		limit = n
+	}
+	if value := r.URL.Query().Get("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return 0, 0, fmt.Errorf("offset must be 0 or more")
+		}
+		offset = n
+	}
+
+	return limit, offset, nil
+}
+
+// pageBounds the slice of total results a page covers
+func pageBounds(total int, limit int, offset int) (int, int) {
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+
+	return offset, end
+}
+
+// dateParam reads a yyyy-mm-dd query parameter, the zero time when it isn't given
+func dateParam(r *http.Request, name string) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return date, fmt.Errorf("%s must be a date as yyyy-mm-dd", name)
+	}
+
+	return date, nil
+}
+
+// gameFilter Given the query of a request, return a test for the games it asks for. It understands team (either
+// side), visitor, home, player, year, from and to.
+func (server *APIServer) gameFilter(r *http.Request) (func(i int) bool, error) {
+	query := r.URL.Query()
+	from, err := dateParam(r, "from")
+	if err != nil {
+		return nil, err
+	}
+	to, err := dateParam(r, "to")
+	if err != nil {
+		return nil, err
+	}
+	year := 0
+	if value := query.Get("year"); value != "" {
+		if year, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("year must be a number")
+		}
+	}
+
+	var played map[int]bool
+	if player := query.Get("player"); player != "" {
+		played = make(map[int]bool)
+		for _, i := range server.players[player] {
+			played[i] = true
+		}
+	}
+
+	team := strings.ToUpper(query.Get("team"))
+	visitor := strings.ToUpper(query.Get("visitor"))
+	home := strings.ToUpper(query.Get("home"))
+
+	return func(i int) bool {
+		summary := server.summaries[i]
+		switch {
+		case team != "" && summary.Visitor.ID != team && summary.Home.ID != team,
+			visitor != "" && summary.Visitor.ID != visitor,
+			home != "" && summary.Home.ID != home,
+			played != nil && !played[i],
+			year != 0 && summary.Date.Year() != year,
+			!from.IsZero() && summary.Date.Before(from),
+			!to.IsZero() && summary.Date.After(to):
+			return false
+		}
+		return true
+	}, nil
+}
+
+// handleGames lists the games, filtered and a page at a time
+func (server *APIServer) handleGames(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	keep, err := server.gameFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	matched := make([]GameSummary, 0)
+	for i, summary := range server.summaries {
+		if keep(i) {
+			matched = append(matched, summary)
+		}
+	}
+
+	start, end := pageBounds(len(matched), limit, offset)
+	writeJSON(w, http.StatusOK, GameList{Total: len(matched), Offset: offset, Limit: limit, Games: matched[start:end]})
+}
+
+// lookupGame finds the game named in the path, sending a 404 when there isn't one
+func (server *APIServer) lookupGame(w http.ResponseWriter, r *http.Request) (int, bool) {
+	i, ok := server.byID[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no game with id '%s'", r.PathValue("id")))
+	}
+
+	return i, ok
+}
+
+// handleGame sends a whole game, the same json a plain run writes to disk
+func (server *APIServer) handleGame(w http.ResponseWriter, r *http.Request) {
+	if i, ok := server.lookupGame(w, r); ok {
+		writeJSON(w, http.StatusOK, server.games[i])
+	}
+}
+
+// handleBoxScore sends the box score of a game
+func (server *APIServer) handleBoxScore(w http.ResponseWriter, r *http.Request) {
+	if i, ok := server.lookupGame(w, r); ok {
+		writeJSON(w, http.StatusOK, server.boxScores[i])
+	}
+}
+
+// handleReplay sends the state of a game at the play and pitch given, the start of the game when they aren't
+func (server *APIServer) handleReplay(w http.ResponseWriter, r *http.Request) {
+	i, ok := server.lookupGame(w, r)
+	if !ok {
+		return
+	}
+
+	position := ReplayPosition{}
+	for name, value := range map[string]*int{"play": &position.Play, "pitch": &position.Pitch} {
+		if param := r.URL.Query().Get(name); param != "" {
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("%s must be a number", name))
+				return
+			}
+			*value = n
+		}
+	}
+
+	replay := CreateReplay(&server.games[i])
+	if err := replay.Seek(position); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, replay.Snapshot())
+}
+
+// handleGameLog lists the games a player appeared in with their batting and pitching lines, taking the same filters
+// and paging as the list of games
+func (server *APIServer) handleGameLog(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	appearances, ok := server.players[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no games found for player '%s'", id))
+		return
+	}
+	limit, offset, err := pageParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	keep, err := server.gameFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	entries := make([]GameLogEntry, 0)
+	for _, i := range appearances {
+		if !keep(i) {
+			continue
+		}
+		summary := server.summaries[i]
+		entry := GameLogEntry{Game: summary}
+		box := server.boxScores[i]
+		for team := 0; team < 2; team++ {
+			for j := range box.Batting[team] {
+				if box.Batting[team][j].ID == id {
+					entry.Batting = &box.Batting[team][j]
+					entry.Home = team == 1
+				}
+			}
+			for j := range box.Pitching[team] {
+				if box.Pitching[team][j].ID == id {
+					entry.Pitching = &box.Pitching[team][j]
+					entry.Home = team == 1
+				}
+			}
+		}
+		entry.Team, entry.Opponent = summary.Visitor.ID, summary.Home.ID
+		if entry.Home {
+			entry.Team, entry.Opponent = summary.Home.ID, summary.Visitor.ID
+		}
+		entries = append(entries, entry)
+	}
+
+	start, end := pageBounds(len(entries), limit, offset)
+	writeJSON(w, http.StatusOK, GameLog{Player: LookupPerson(id), Total: len(entries), Offset: offset, Limit: limit, Games: entries[start:end]})
+}
+
+// handleTeamSeason sends a team's record and games for a season
+func (server *APIServer) handleTeamSeason(w http.ResponseWriter, r *http.Request) {
+	code := strings.ToUpper(r.PathValue("code"))
+	year, err := strconv.Atoi(r.PathValue("year"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("year must be a number"))
+		return
+	}
+
+	season := TeamSeason{Year: year, Games: make([]TeamGame, 0)}
+	for _, summary := range server.summaries {
+		if summary.Date.Year() != year || (summary.Visitor.ID != code && summary.Home.ID != code) {
+			continue
+		}
+
+		home := summary.Home.ID == code
+		team, opponent := 0, 1
+		if home {
+			team, opponent = 1, 0
+			season.Team = summary.Home
+		} else {
+			season.Team = summary.Visitor
+		}
+		game := TeamGame{
+			Game:         summary,
+			Opponent:     []Team{summary.Visitor, summary.Home}[opponent].ID,
+			Home:         home,
+			Runs:         summary.Runs[team],
+			OpponentRuns: summary.Runs[opponent],
+		}
+		switch {
+		case game.Runs > game.OpponentRuns:
+			game.Decision = "W"
+			season.Wins++
+		case game.Runs < game.OpponentRuns:
+			game.Decision = "L"
+			season.Losses++
+		default:
+			game.Decision = "T"
+			season.Ties++
+		}
+		season.RunsScored += game.Runs
+		season.RunsAllowed += game.OpponentRuns
+		season.Games = append(season.Games, game)
+	}
+
+	if len(season.Games) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no games found for team '%s' in %d", code, year))
+		return
+	}
+	writeJSON(w, http.StatusOK, season)
+}
+
+// Handler the routes of the API. Everything is read only, and any origin may call it so a frontend can be served from
+// elsewhere.
+func (server *APIServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /games", server.handleGames)
+	mux.HandleFunc("GET /games/{id}", server.handleGame)
+	mux.HandleFunc("GET /games/{id}/boxscore", server.handleBoxScore)
+	mux.HandleFunc("GET /games/{id}/feed", server.handleFeed)
+	mux.HandleFunc("GET /games/{id}/replay", server.handleReplay)
+	mux.HandleFunc("GET /players/{id}/gamelog", server.handleGameLog)
+	mux.HandleFunc("GET /teams/{code}/season/{year}", server.handleTeamSeason)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		mux.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIServerGames(t *testing.T) {
+	data, err := ioutil.ReadFile(eventPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := CreateAPIServer(CreateGames(data), nil).Handler()
+
+	tests := []struct {
+		query  string
+		status int
+		// total is how many games match, page how many of them come back
+		total int
+		page  int
+	}{
+		{"", http.StatusOK, 81, apiDefaultLimit},
+		{"?limit=10&offset=75", http.StatusOK, 81, 6},
+		{"?offset=81", http.StatusOK, 81, 0},
+		{"?offset=100", http.StatusOK, 81, 0},
+		{"?limit=500", http.StatusOK, 81, 81},
+		{"?limit=0", http.StatusBadRequest, 0, 0},
+		{"?limit=501", http.StatusBadRequest, 0, 0},
+		{"?limit=ten", http.StatusBadRequest, 0, 0},
+		{"?offset=-1", http.StatusBadRequest, 0, 0},
+		{"?team=nyn", http.StatusOK, 81, apiDefaultLimit},
+		{"?visitor=SLN", http.StatusOK, 9, 9},
+		{"?home=SLN", http.StatusOK, 0, 0},
+		{"?team=SLN&home=NYN", http.StatusOK, 9, 9},
+		{"?player=colev001", http.StatusOK, 9, 9},
+		{"?player=colev001&visitor=CHN", http.StatusOK, 0, 0},
+		{"?player=nobody", http.StatusOK, 0, 0},
+		{"?year=1986&limit=5", http.StatusOK, 81, 5},
+		{"?year=1985", http.StatusOK, 0, 0},
+		{"?year=last", http.StatusBadRequest, 0, 0},
+		{"?from=1986-04-14&to=1986-04-30", http.StatusOK, 6, 6},
+		{"?from=1986-10-01", http.StatusOK, 3, 3},
+		{"?from=1986-10-05", http.StatusOK, 1, 1},
+		{"?to=1986-04-14", http.StatusOK, 1, 1},
+		{"?to=04/30/1986", http.StatusBadRequest, 0, 0},
+	}
+
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/games"+test.query, nil))
+		if recorder.Code != test.status {
+			t.Errorf("%s: want status %d, got %d %s", test.query, test.status, recorder.Code, recorder.Body.String())
+			continue
+		}
+		if test.status != http.StatusOK {
+			continue
+		}
+
+		list := GameList{}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &list); err != nil {
+			t.Fatalf("%s: %v", test.query, err)
+		}
+		if list.Total != test.total || len(list.Games) != test.page {
+			t.Errorf("%s: want %d games with %d on the page, got %d with %d", test.query, test.total, test.page, list.Total, len(list.Games))
+		}
+	}
+}
@@ -0,0 +1,508 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// simOutcomes the kinds of plate appearance the simulator picks between
+var simOutcomes = []string{"K", "BB", "HBP", "1B", "2B", "3B", "HR", "GO", "AO", "E"}
+
+// simBatterWeight and simPitcherWeight how many league average plate appearances are mixed into a player's own, so a
+// few lucky games don't make a .500 hitter
+const (
+	simBatterWeight  = 100
+	simPitcherWeight = 200
+)
+
+// simStarterOuts how long a starter goes when there's no record of them starting, simRunsPulled how many runs they
+// give up before they're taken out regardless, and simMaxInnings when a game that's still tied is called
+const (
+	simStarterOuts = 18
+	simRunsPulled  = 5
+	simMaxInnings  = 25
+)
+
+// simOutcome Given an event, return which of simOutcomes it is, blank for anything that isn't a plate appearance
+func simOutcome(event Event) string {
+	switch event.Type {
+	case EventStrikeout:
+		return "K"
+	case EventWalk, EventIntentionalWalk:
+		return "BB"
+	case EventHitByPitch:
+		return "HBP"
+	case EventSingle:
+		return "1B"
+	case EventDouble:
+		return "2B"
+	case EventTriple:
+		return "3B"
+	case EventHomeRun:
+		return "HR"
+	case EventError, EventInterference:
+		return "E"
+	case EventGenericOut, EventFieldersChoice:
+		trajectory, _, _ := battedBall(event)
+		// a play with a throw in it, like 63, was on the ground
+		throws := len(event.BasicPlay) > 1 && strings.IndexAny(event.BasicPlay[1:], "123456789") >= 0
+		switch {
+		case trajectory == "G", trajectory == "" && (throws || event.Type == EventFieldersChoice):
+			return "GO"
+		}
+		return "AO"
+	}
+
+	return ""
+}
+
+// simRates how often each outcome happens, counted up from plate appearances
+type simRates map[string]float64
+
+// total the plate appearances the rates were counted from
+func (rates simRates) total() float64 {
+	total := 0.0
+	for _, count := range rates {
+		total += count
+	}
+
+	return total
+}
+
+// SimulationTeam one side of a simulated game: the starting lineup, pitcher included, and the relievers to call on
+type SimulationTeam struct {
+	Team    string
+	Lineup  []Player
+	Bullpen []string
+}
+
+// SimulationModel what the simulator knows from the games it was built from: how often each batter and pitcher
+// produces each outcome, the plays that happened in each base-out state, how long starters last and who pitches in
+// relief for each team
+type SimulationModel struct {
+	league   simRates
+	batters  map[string]simRates
+	pitchers map[string]simRates
+	// plays holds the events of real plate appearances by outcome and base-out state, to draw what happens on the bases from
+	plays map[string]map[BaseOutState][]Play
+	// starterOuts holds the outs recorded in each start by each pitcher, relief the relief appearances by team then pitcher
+	starterOuts map[string][]int
+	relief      map[string]map[string]int
+	// lineups holds the latest starting lineup of each team, parks each team's home park
+	lineups map[string][]Player
+	parks   map[string]string
+	date    Date
+}
+
+// BuildSimulationModel Given a collection of games, count up everything the simulator draws on
+func BuildSimulationModel(games []Game) *SimulationModel {
+	model := &SimulationModel{
+		league:      simRates{},
+		batters:     make(map[string]simRates),
+		pitchers:    make(map[string]simRates),
+		plays:       make(map[string]map[BaseOutState][]Play),
+		starterOuts: make(map[string][]int),
+		relief:      make(map[string]map[string]int),
+		lineups:     make(map[string][]Player),
+		parks:       make(map[string]string),
+	}
+
+	sorted := make([]Game, len(games))
+	copy(sorted, games)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Info.Date.Before(sorted[j].Info.Date.Time)
+	})
+
+	for _, game := range sorted {
+		model.date = game.Info.Date
+		model.parks[game.Info.Hometeam.ID] = game.Info.Site.ID
+		teams := [2]string{game.Info.Visteam.ID, game.Info.Hometeam.ID}
+		model.lineups[teams[0]] = game.Lineup.Visitor
+		model.lineups[teams[1]] = game.Lineup.Home
+
+		for _, play := range game.Plays {
+			for _, sub := range play.Substitutions {
+				if sub.Player.FieldingPosition.ID == "1" {
+					if model.relief[teams[sub.Team]] == nil {
+						model.relief[teams[sub.Team]] = make(map[string]int)
+					}
+					model.relief[teams[sub.Team]][sub.Player.ID]++
+				}
+			}
+
+			outcome := simOutcome(play.Event)
+			if outcome == "" {
+				continue
+			}
+			model.league[outcome]++
+			for _, count := range []struct {
+				rates map[string]simRates
+				id    string
+			}{{model.batters, play.BatterID}, {model.pitchers, play.PitcherID}} {
+				if count.rates[count.id] == nil {
+					count.rates[count.id] = simRates{}
+				}
+				count.rates[count.id][outcome]++
+			}
+
+			if model.plays[outcome] == nil {
+				model.plays[outcome] = make(map[BaseOutState][]Play)
+			}
+			state := play.Before.BaseOut()
+			model.plays[outcome][state] = append(model.plays[outcome][state], play)
+		}
+
+		box := CreateBoxScore(game)
+		for team := 0; team < 2; team++ {
+			if len(box.Pitching[team]) > 0 {
+				starter := box.Pitching[team][0]
+				model.starterOuts[starter.ID] = append(model.starterOuts[starter.ID], starter.Outs)
+			}
+		}
+	}
+
+	return model
+}
+
+// Team Given a team code, return the team's latest starting lineup and its relievers, most used first
+func (model *SimulationModel) Team(code string) (SimulationTeam, error) {
+	lineup, ok := model.lineups[code]
+	if !ok {
+		return SimulationTeam{}, fmt.Errorf("no games found for team '%s'", code)
+	}
+
+	team := SimulationTeam{Team: code, Lineup: lineup}
+	for id := range model.relief[code] {
+		team.Bullpen = append(team.Bullpen, id)
+	}
+	sort.Slice(team.Bullpen, func(i, j int) bool {
+		a, b := model.relief[code][team.Bullpen[i]], model.relief[code][team.Bullpen[j]]
+		if a != b {
+			return a > b
+		}
+		return team.Bullpen[i] < team.Bullpen[j]
+	})
+
+	return team, nil
+}
+
+// starterLength the outs a pitcher is left in for when starting, their average start
+func (model *SimulationModel) starterLength(id string) int {
+	starts := model.starterOuts[id]
+	if len(starts) == 0 {
+		return simStarterOuts
+	}
+
+	total := 0
+	for _, outs := range starts {
+		total += outs
+	}
+
+	return total / len(starts)
+}
+
+// matchup the chance of each outcome for a batter facing a pitcher. Each side's rates are mixed with the league's, then
+// combined with the odds ratio method: the batter's rate times the pitcher's, over the league's.
+func (model *SimulationModel) matchup(batter string, pitcher string) []float64 {
+	leagueTotal := model.league.total()
+	mixed := func(rates simRates, weight float64, outcome string) float64 {
+		league := model.league[outcome] / leagueTotal
+		return (rates[outcome] + weight*league) / (rates.total() + weight)
+	}
+
+	chances := make([]float64, len(simOutcomes))
+	for i, outcome := range simOutcomes {
+		if model.league[outcome] == 0 {
+			continue
+		}
+		league := model.league[outcome] / leagueTotal
+		chances[i] = mixed(model.batters[batter], simBatterWeight, outcome) * mixed(model.pitchers[pitcher], simPitcherWeight, outcome) / league
+	}
+
+	return chances
+}
+
+// pick Given weights, choose an index in proportion to them
+func pick(rng *rand.Rand, weights []float64) int {
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+
+	r := rng.Float64() * total
+	for i, weight := range weights {
+		if r < weight {
+			return i
+		}
+		r -= weight
+	}
+
+	return len(weights) - 1
+}
+
+// simDefaultEvent writes the event of an outcome for when no real play fits the situation: hits move runners up by the
+// bases of the hit, walks only move runners who are forced, and outs leave them where they are
+func simDefaultEvent(outcome string, bases [3]string) string {
+	plays := map[string]string{"K": "K", "BB": "W", "HBP": "HP", "1B": "S8", "2B": "D8", "3B": "T8", "HR": "HR", "GO": "63/G", "AO": "8/F", "E": "E6"}
+	gains := map[string]int{"1B": 1, "2B": 2, "3B": 3, "HR": 4}
+
+	advances := make([]string, 0)
+	forced := true
+	for base := 1; base <= 3; base++ {
+		if bases[base-1] == "" {
+			forced = false
+			continue
+		}
+		to := base
+		switch {
+		case gains[outcome] > 0:
+			to = base + gains[outcome]
+		case (outcome == "BB" || outcome == "HBP" || outcome == "E") && forced:
+			to = base + 1
+		}
+		if to > Home {
+			to = Home
+		}
+		if to != base {
+			advances = append([]string{fmt.Sprintf("%s-%s", BaseCode(base), BaseCode(to))}, advances...)
+		}
+	}
+
+	if len(advances) == 0 {
+		return plays[outcome]
+	}
+
+	return plays[outcome] + "." + strings.Join(advances, ";")
+}
+
+// event draws the event of a plate appearance with the given outcome from the real plays in the same base-out state,
+// making one up when there aren't any
+func (model *SimulationModel) event(rng *rand.Rand, outcome string, situation Situation) string {
+	if plays := model.plays[outcome][situation.BaseOut()]; len(plays) > 0 {
+		return plays[rng.Intn(len(plays))].Event.Source
+	}
+
+	return simDefaultEvent(outcome, situation.Bases)
+}
+
+// simSide a team as the simulation goes: who's due up and who's pitching
+type simSide struct {
+	up      int
+	pitcher Player
+	// outs and runs are what the pitcher on the mound has recorded and allowed, bullpen the relievers not used yet
+	outs    int
+	runs    int
+	length  int
+	bullpen []string
+}
+
+// simGame a game being simulated, with the records written for it so far
+type simGame struct {
+	model   *SimulationModel
+	rng     *rand.Rand
+	sides   [2]*simSide
+	state   GameState
+	records []string
+}
+
+// simFinal whether a game is over: the home team ahead once the visitor has batted in the ninth or later, or either
+// team ahead at the end of an inning from the ninth on
+func simFinal(state GameState) bool {
+	if state.Inning < 9 {
+		return false
+	}
+
+	return (state.Score[1] > state.Score[0] && (state.Half == 1 || state.Outs == 3)) ||
+		(state.Half == 1 && state.Outs == 3 && state.Score[0] != state.Score[1])
+}
+
+// Simulate plays out a game between two teams, returning it as a Game built from the event file records the
+// simulation writes, so it works with everything a parsed game does. Ids have the usual home team, date and game number
+// layout, the game being dated the day of the latest game the model was built from and number telling simulated games
+// apart, so it can run past one digit.
+func (model *SimulationModel) Simulate(visitor SimulationTeam, home SimulationTeam, rng *rand.Rand, number int) Game {
+	usedh := len(visitor.Lineup) > 9 && len(home.Lineup) > 9
+	sim := &simGame{model: model, rng: rng}
+	sim.records = []string{
+		fmt.Sprintf("id,%s%08d%d", home.Team, model.date.number(), number),
+		"version,2",
+		"info,visteam," + visitor.Team,
+		"info,hometeam," + home.Team,
+		"info,site," + model.parks[home.Team],
+		"info,date," + model.date.String(),
+		"info,number,0",
+		fmt.Sprintf("info,usedh,%t", usedh),
+	}
+
+	starts := make([]string, 0)
+	for team, side := range []SimulationTeam{visitor, home} {
+		s := &simSide{up: 1}
+		for _, player := range simLineup(side.Lineup, usedh) {
+			if player.FieldingPosition.ID == "1" {
+				s.pitcher = player
+			}
+			starts = append(starts, eventPlayerRecord("start", player, team))
+		}
+		s.length = model.starterLength(s.pitcher.ID)
+		for _, id := range side.Bullpen {
+			if id != s.pitcher.ID {
+				s.bullpen = append(s.bullpen, id)
+			}
+		}
+		sim.sides[team] = s
+	}
+
+	sim.records = append(sim.records, starts...)
+	sim.state = NewGameState(CreateLineup(GetRecords(starts), usedh))
+
+	return sim.run()
+}
+
+// run plays the game out from wherever its state is, the middle of a half inning included
+func (sim *simGame) run() Game {
+	state := &sim.state
+	for !simFinal(*state) {
+		if state.Outs == 3 {
+			inning, team := state.Inning+state.Half, 1-state.Half
+			if inning > simMaxInnings {
+				break
+			}
+
+			fielding := sim.sides[1-team]
+			if fielding.outs >= fielding.length || fielding.runs >= simRunsPulled {
+				if reliever, ok := fielding.relieve(); ok {
+					sim.records = append(sim.records, eventPlayerRecord("sub", reliever, 1-team))
+					state.Substitute(Substitution{Team: 1 - team, Player: reliever})
+				}
+			}
+			state.Enter(inning, team)
+		}
+
+		batting, fielding := sim.sides[state.Half], sim.sides[1-state.Half]
+		batter := state.Lineups[state.Half][batting.up]
+		chances := sim.model.matchup(batter, fielding.pitcher.ID)
+		event := sim.model.event(sim.rng, simOutcomes[pick(sim.rng, chances)], state.Situation)
+		parsed, _ := ParseEvent(event)
+
+		outs := state.Outs
+		runs, _, _, _ := state.Apply(state.Inning, state.Half, batter, parsed)
+		fielding.outs += state.Outs - outs
+		fielding.runs += runs
+		batting.up = batting.up%9 + 1
+		sim.records = append(sim.records, fmt.Sprintf("play,%d,%d,%s,??,,%s", state.Inning, state.Half, batter, event))
+	}
+
+	return CreateGame(strings.Join(sim.records, "\r\n"))
+}
+
+// relieve brings in the next reliever, returning false when the team is out of them and leaves its pitcher in
+func (side *simSide) relieve() (Player, bool) {
+	if len(side.bullpen) == 0 {
+		return Player{}, false
+	}
+
+	reliever := Player{
+		ID:               side.bullpen[0],
+		Name:             LookupPerson(side.bullpen[0]).FullName(),
+		BattingPosition:  side.pitcher.BattingPosition,
+		FieldingPosition: FieldingPositions["1"],
+	}
+	side.bullpen = side.bullpen[1:]
+	side.pitcher = reliever
+	// relievers go an inning at a time
+	side.outs, side.runs, side.length = 0, 0, 3
+
+	return reliever, true
+}
+
+// simLineup fits a starting lineup to whether the game has a DH, the pitcher batting in the DH's place when it doesn't
+func simLineup(players []Player, usedh bool) []Player {
+	if usedh || len(players) <= 9 {
+		return players
+	}
+
+	lineup := make([]Player, 0)
+	dhSlot := 0
+	for _, player := range players {
+		if player.FieldingPosition.ID == "10" {
+			dhSlot = player.BattingPosition
+		}
+	}
+	for _, player := range players {
+		switch player.FieldingPosition.ID {
+		case "10":
+			continue
+		case "1":
+			player.BattingPosition = dhSlot
+		}
+		lineup = append(lineup, player)
+	}
+
+	return lineup
+}
+
+// SimulationResult the totals of many simulated games between the same two teams
+type SimulationResult struct {
+	Games int    `json:"games"`
+	Wins  [2]int `json:"wins"`
+	Ties  int    `json:"ties"`
+	// Runs is the total runs of each team, Margins how many games were won by each run differential, home minus visitor
+	Runs    [2]int      `json:"runs"`
+	Margins map[int]int `json:"margins"`
+}
+
+// WinPercentage the share of decided games a team won, 0 for the visitor and 1 for the home team
+func (result SimulationResult) WinPercentage(team int) float64 {
+	decided := result.Wins[0] + result.Wins[1]
+	if decided == 0 {
+		return 0
+	}
+
+	return float64(result.Wins[team]) / float64(decided)
+}
+
+// AverageRuns the runs a team scored per game
+func (result SimulationResult) AverageRuns(team int) float64 {
+	if result.Games == 0 {
+		return 0
+	}
+
+	return float64(result.Runs[team]) / float64(result.Games)
+}
+
+// Add counts a game's final score into the totals
+func (result *SimulationResult) Add(game Game) {
+	if result.Margins == nil {
+		result.Margins = make(map[int]int)
+	}
+
+	score := game.Plays[len(game.Plays)-1].After.Score
+	result.Games++
+	result.Runs[0] += score[0]
+	result.Runs[1] += score[1]
+	result.Margins[score[1]-score[0]]++
+	switch {
+	case score[0] > score[1]:
+		result.Wins[0]++
+	case score[1] > score[0]:
+		result.Wins[1]++
+	default:
+		result.Ties++
+	}
+}
+
+// Project simulates games between two teams, returning their totals along with the games themselves
+func (model *SimulationModel) Project(visitor SimulationTeam, home SimulationTeam, games int, rng *rand.Rand) (SimulationResult, []Game) {
+	result := SimulationResult{Margins: make(map[int]int)}
+	simulated := make([]Game, 0)
+
+	for i := 1; i <= games; i++ {
+		game := model.Simulate(visitor, home, rng, i)
+		simulated = append(simulated, game)
+		result.Add(game)
+	}
+
+	return result, simulated
+}
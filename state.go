@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// BaseOutState one of the 24 combinations of outs and occupied bases
+type BaseOutState struct {
+	Outs int `json:"outs"`
+	// Runners is a bit mask of occupied bases: 1 for first, 2 for second, 4 for third
+	Runners int `json:"runners"`
+}
+
+// String formats the state as occupied bases followed by outs, e.g. "1_3 2"
+func (state BaseOutState) String() string {
+	bases := []byte("___")
+	for i := 0; i < 3; i++ {
+		if state.Runners&(1<<uint(i)) != 0 {
+			bases[i] = byte('1' + i)
+		}
+	}
+
+	return fmt.Sprintf("%s %d", bases, state.Outs)
+}
+
+// Situation the outs, base runners and score at a point in a game
+type Situation struct {
+	Outs  int       `json:"outs"`
+	Bases [3]string `json:"bases"`
+	Score [2]int    `json:"score"`
+}
+
+// BaseOut the base-out state for the situation
+func (situation Situation) BaseOut() BaseOutState {
+	state := BaseOutState{Outs: situation.Outs}
+	for i, runner := range situation.Bases {
+		if runner != "" {
+			state.Runners |= 1 << uint(i)
+		}
+	}
+
+	return state
+}
+
+// GameState tracks everything about a game that changes as its records are replayed
+type GameState struct {
+	Inning int `json:"inning"`
+	// Half is the team at bat, 0 for the visitor and 1 for the home team
+	Half int `json:"half"`
+	Situation
+	// Lineups holds player ids by batting order slot for each team, slot 0 is the pitcher when a DH is used
+	Lineups [2][10]string `json:"lineups"`
+	// Defense holds player ids by fielding position for each team, 10 is the DH
+	Defense [2][11]string `json:"defense"`
+}
+
+// NewGameState Given the starting lineups, return the state of the game before the first pitch
+func NewGameState(lineup Lineup) GameState {
+	state := GameState{Inning: 1}
+
+	for team, players := range [2][]Player{lineup.Visitor, lineup.Home} {
+		for _, player := range players {
+			state.place(team, player)
+		}
+	}
+
+	return state
+}
+
+// place puts a player into a batting order slot and fielding position
+func (state *GameState) place(team int, player Player) {
+	if player.BattingPosition >= 0 && player.BattingPosition < len(state.Lineups[team]) {
+		state.Lineups[team][player.BattingPosition] = player.ID
+	}
+
+	position, _ := strconv.Atoi(player.FieldingPosition.ID)
+	if position >= 1 && position <= 10 {
+		state.Defense[team][position] = player.ID
+	}
+}
+
+// Pitcher the id of the pitcher currently on the mound for the team in the field
+func (state *GameState) Pitcher() string {
+	return state.Defense[1-state.Half][1]
+}
+
+// Substitute applies a sub record, returning the id of the player that was replaced
+func (state *GameState) Substitute(sub Substitution) string {
+	replaced := ""
+	if sub.Player.BattingPosition >= 0 && sub.Player.BattingPosition < len(state.Lineups[sub.Team]) {
+		replaced = state.Lineups[sub.Team][sub.Player.BattingPosition]
+	}
+
+	if sub.Player.FieldingPosition.ID == "12" {
+		// a pinch runner takes over the base of the player being replaced
+		for i, runner := range state.Bases {
+			if runner != "" && runner == replaced {
+				state.Bases[i] = sub.Player.ID
+			}
+		}
+	}
+
+	state.place(sub.Team, sub.Player)
+
+	return replaced
+}
+
+// Enter moves the game to the given half inning if it is not already there, clearing the outs and bases.
+// An error is returned when the half inning being left did not end with three outs.
+func (state *GameState) Enter(inning int, team int) error {
+	if inning == state.Inning && team == state.Half {
+		return nil
+	}
+
+	outs := state.Outs
+	state.Inning = inning
+	state.Half = team
+	state.Outs = 0
+	state.Bases = [3]string{}
+
+	if outs < 3 {
+		return fmt.Errorf("half inning ended with %d outs", outs)
+	}
+
+	return nil
+}
+
+// Apply advances the game state with the event of a play record.
+// It returns the runs scored, the runs batted in, the ids of runners who scored and any inconsistencies found.
+func (state *GameState) Apply(inning int, team int, batterID string, event Event) (int, int, []string, []error) {
+	problems := make([]error, 0)
+	if err := state.Enter(inning, team); err != nil {
+		problems = append(problems, err)
+	}
+
+	advances := make(map[int]Advance)
+	for _, advance := range event.Advances {
+		advances[advance.From] = advance
+	}
+
+	// runners that are not mentioned but are forced by the batter reaching base move up one base
+	if batter, ok := advances[0]; ok && !batter.Out {
+		for base := 1; base <= 3 && state.Bases[base-1] != ""; base++ {
+			if _, ok := advances[base]; ok {
+				break
+			}
+			advances[base] = Advance{From: base, To: base + 1}
+		}
+	}
+
+	runs := 0
+	rbi := 0
+	scorers := make([]string, 0)
+	bases := [3]string{}
+	put := func(runner string, base int) {
+		if bases[base-1] != "" {
+			problems = append(problems, fmt.Errorf("%s and %s both on %s base", bases[base-1], runner, Bases[BaseCode(base)]))
+		}
+		bases[base-1] = runner
+	}
+
+	for from := 3; from >= 0; from-- {
+		runner := batterID
+		if from > 0 {
+			runner = state.Bases[from-1]
+		}
+
+		advance, ok := advances[from]
+		if !ok {
+			if from > 0 && runner != "" {
+				put(runner, from)
+			}
+			continue
+		}
+
+		if runner == "" {
+			problems = append(problems, fmt.Errorf("advance from %s base with no runner on it", Bases[BaseCode(from)]))
+			continue
+		}
+
+		switch {
+		case advance.Out:
+			state.Outs++
+		case advance.To == Home:
+			runs++
+			scorers = append(scorers, runner)
+			if advance.RBI || (event.AwardsRBI() && !advance.NoRBI) {
+				rbi++
+			}
+		default:
+			put(runner, advance.To)
+		}
+	}
+
+	if state.Outs > 3 {
+		problems = append(problems, fmt.Errorf("%d outs in the inning", state.Outs))
+	}
+
+	state.Bases = bases
+	state.Score[team] += runs
+
+	return runs, rbi, scorers, problems
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"strings"
+)
+
+// svgTeams names each side for file names
+var svgTeams = [2]string{"visitor", "home"}
+
+// svgCanvas draws a scorecard as svg elements
+type svgCanvas struct {
+	b strings.Builder
+}
+
+// svgPoints formats points for a polygon or polyline
+func svgPoints(points [][2]float64) string {
+	list := make([]string, 0)
+	for _, p := range points {
+		list = append(list, fmt.Sprintf("%g,%g", p[0], p[1]))
+	}
+
+	return strings.Join(list, " ")
+}
+
+func (c *svgCanvas) Rect(x float64, y float64, width float64, height float64) {
+	fmt.Fprintf(&c.b, `<rect x="%g" y="%g" width="%g" height="%g" fill="none" stroke="#333" stroke-width="1"/>`+"\n", x, y, width, height)
+}
+
+func (c *svgCanvas) Polygon(points [][2]float64, fill string, stroke string, width float64) {
+	if fill == "" {
+		fill = "none"
+	}
+	fmt.Fprintf(&c.b, `<polygon points="%s" fill="%s" stroke="%s" stroke-width="%g"/>`+"\n", svgPoints(points), fill, stroke, width)
+}
+
+func (c *svgCanvas) Polyline(points [][2]float64, stroke string, width float64) {
+	fmt.Fprintf(&c.b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%g"/>`+"\n", svgPoints(points), stroke, width)
+}
+
+func (c *svgCanvas) Circle(cx float64, cy float64, r float64, stroke string) {
+	fmt.Fprintf(&c.b, `<circle cx="%g" cy="%g" r="%g" fill="none" stroke="%s" stroke-width="1"/>`+"\n", cx, cy, r, stroke)
+}
+
+func (c *svgCanvas) Text(x float64, y float64, anchor string, size float64, bold bool, text string) {
+	weight := "normal"
+	if bold {
+		weight = "bold"
+	}
+	fmt.Fprintf(&c.b, `<text x="%g" y="%g" text-anchor="%s" font-size="%g" font-weight="%s">%s</text>`+"\n", x, y, anchor, size, weight, html.EscapeString(text))
+}
+
+// toSVG Renders the scorecard of one team (0 visitor, 1 home) in a game as an svg document, its labels in the
+// language of the catalog
+func (game *Game) toSVG(team int, catalog Catalog) string {
+	scorecard := CreateScorecard(*game, team)
+	linescore := CreateLinescore(game.Plays)
+	width, height := scorecardSize(game, scorecard, linescore)
+
+	c := &svgCanvas{}
+	fmt.Fprintf(&c.b, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" font-family="Helvetica, Arial, sans-serif">`+"\n", width, height)
+	fmt.Fprintf(&c.b, `<rect width="100%%" height="100%%" fill="#fff"/>`+"\n")
+	drawScorecard(c, game, scorecard, linescore, catalog)
+	fmt.Fprintf(&c.b, "</svg>\n")
+
+	return c.b.String()
+}
+
+// Game.toSVGDisk() Renders the scorecards of both teams and writes them as svg files to the given output path
+func (game *Game) toSVGDisk(path string, catalog Catalog) {
+	for team, side := range svgTeams {
+		outputPath := fmt.Sprintf("%s/%s-%s.svg", path, game.ID, side)
+
+		err := ioutil.WriteFile(outputPath, []byte(game.toSVG(team, catalog)), 0644)
+		if err != nil {
+			panic("Error occurred attempting to write scorecard file")
+		}
+	}
+}
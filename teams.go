@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// referenceDate converts a date written as yyyy/mm/dd, as in event files, m/d/yyyy or yyyymmdd, as in reference files,
+// or yyyy-mm-dd to a yyyymmdd number. A blank or unreadable date is 0.
+func referenceDate(date string) int {
+	date = strings.TrimSpace(date)
+	if len(date) == 8 && !strings.ContainsAny(date, "/-") {
+		n, err := strconv.Atoi(date)
+		if err != nil {
+			return 0
+		}
+		return n
+	}
+
+	parts := strings.FieldsFunc(date, func(r rune) bool { return r == '/' || r == '-' })
+	if len(parts) != 3 {
+		return 0
+	}
+	if len(parts[0]) != 4 {
+		parts = []string{parts[2], parts[0], parts[1]}
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0
+		}
+		numbers[i] = n
+	}
+
+	return numbers[0]*10000 + numbers[1]*100 + numbers[2]
+}
+
+// isoDate rewrites a reference date as yyyy-mm-dd, blank when it can't be read
+func isoDate(date string) string {
+	day := referenceDate(date)
+	if day == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%04d-%02d-%02d", day/10000, day/100%100, day%100)
+}
+
+// leagueByCode finds a league by its two letter code, leagues without reference data just carry the code
+func leagueByCode(code string) League {
+	for _, league := range Leagues {
+		if league.Code == code {
+			return league
+		}
+	}
+
+	return League{ID: code, Code: code, Name: code}
+}
+
+// LookupTeam Given a team code and the date of a game, return the team as it was on that day.
+// The season's TEAMYYYY file gives the name the team went by, the franchise history fills in its city and current franchise,
+// and when neither knows the code for that day the latest Teams entry is used. A code missing from all of them gives a team named by its code.
+func LookupTeam(code string, date Date) Team {
+	team, ok := findTeam(code, date)
+	if !ok && code != "" {
+		return Team{ID: code, Name: code}
+	}
+
+	return team
+}
+
+// findTeam looks a team code up for the date of a game, reporting whether the reference data has it
+func findTeam(code string, date Date) (Team, bool) {
+	day := date.number()
+	team, ok := TeamSeasons[day/10000][code]
+
+	for _, era := range TeamHistory[code] {
+		if day < era.From || (era.To != 0 && day > era.To) {
+			continue
+		}
+		if !ok {
+			return era.Team, true
+		}
+
+		team.City = era.Team.City
+		team.Franchise = era.Team.Franchise
+		team.Division = era.Team.Division
+		return team, true
+	}
+
+	if ok {
+		return team, true
+	}
+	team, ok = Teams[code]
+
+	return team, ok
+}
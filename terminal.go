@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TextOptions controls how a game is rendered for the terminal
+type TextOptions struct {
+	// Color highlights hits, outs and runs with ANSI colour codes
+	Color bool
+	// ASCII sticks to plain ascii characters for terminals without unicode fonts
+	ASCII bool
+	// Catalog is the language of the labels, English when it's left empty
+	Catalog Catalog
+}
+
+// ANSI colour codes used in the terminal view
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[1;33m"
+	ansiBold   = "\x1b[1m"
+)
+
+// widths of the columns in the terminal view
+const (
+	textNameWidth = 26
+	textCellWidth = 10
+)
+
+// textOutMarks marks the out number made on a plate appearance
+var textOutMarks = map[bool][]string{
+	false: {"", "①", "②", "③"},
+	true:  {"", "(1)", "(2)", "(3)"},
+}
+
+// colorize wraps text in a colour code when colour is turned on
+func (options TextOptions) colorize(text string, color string) string {
+	if !options.Color || color == "" {
+		return text
+	}
+
+	return color + text + ansiReset
+}
+
+// runeWidth how many columns a character takes up in a terminal, two for the CJK characters and full width forms that
+// Japanese labels are written with
+func runeWidth(r rune) int {
+	switch {
+	case r >= 0x1100 && r <= 0x115F, r >= 0x2E80 && r <= 0xA4CF, r >= 0xAC00 && r <= 0xD7A3, r >= 0xF900 && r <= 0xFAFF, r >= 0xFF00 && r <= 0xFF60:
+		return 2
+	}
+
+	return 1
+}
+
+// truncate shortens a string to fit a column
+func truncate(text string, width int) string {
+	used := 0
+	for i, r := range text {
+		if used+runeWidth(r) > width {
+			return text[:i]
+		}
+		used += runeWidth(r)
+	}
+
+	return text
+}
+
+// textWidth how many columns a string takes up in a terminal
+func textWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		width += runeWidth(r)
+	}
+
+	return width
+}
+
+// pad fills a string out to a column width before any colour is applied, so the codes don't throw off alignment
+func pad(text string, width int) string {
+	text = truncate(text, width)
+
+	return text + strings.Repeat(" ", width-textWidth(text))
+}
+
+// padLeft right aligns a string in a column
+func padLeft(text string, width int) string {
+	text = truncate(text, width)
+
+	return strings.Repeat(" ", width-textWidth(text)) + text
+}
+
+// textBox renders one plate appearance as a short code followed by how it ended
+func textBox(box ScorecardBox, options TextOptions) string {
+	mark := ""
+	color := ""
+
+	switch {
+	case box.Out > 0:
+		mark = textOutMarks[options.ASCII][box.Out]
+		color = ansiRed
+	case box.Bases == Home:
+		mark = "◆"
+		if options.ASCII {
+			mark = "*"
+		}
+		color = ansiYellow
+	case box.Bases > 0:
+		mark = fmt.Sprintf(">%d", box.Bases)
+		if !options.ASCII {
+			mark = fmt.Sprintf("→%d", box.Bases)
+		}
+	}
+
+	if color == "" && box.Hit {
+		color = ansiGreen
+	}
+
+	// keep a space between cells even when the code fills the column
+	text := truncate(strings.TrimSpace(box.Code+" "+mark), textCellWidth-1)
+
+	return options.colorize(pad(text, textCellWidth), color)
+}
+
+// textScorecard renders one team's scorecard grid
+func textScorecard(scorecard Scorecard, options TextOptions) []string {
+	lines := make([]string, 0)
+
+	header := pad(scorecard.Name, textNameWidth)
+	for i, inning := range scorecard.Columns {
+		label := ""
+		if i == 0 || scorecard.Columns[i-1] != inning {
+			label = fmt.Sprintf("%d", inning)
+		}
+		header += pad(label, textCellWidth)
+	}
+	lines = append(lines, options.colorize(header, ansiBold))
+
+	for slot, players := range scorecard.Slots {
+		cells := make([]string, len(scorecard.Columns))
+		for i := range cells {
+			cells[i] = pad("", textCellWidth)
+		}
+		for _, box := range players.Boxes {
+			cells[box.Column] = textBox(box, options)
+		}
+
+		for i, entry := range players.Players {
+			label := fmt.Sprintf("%d %s", slot+1, describeEntry(entry))
+			row := ""
+			if i > 0 {
+				label = "  " + describeEntry(entry)
+			} else {
+				row = strings.Join(cells, "")
+			}
+			lines = append(lines, pad(label, textNameWidth)+row)
+		}
+	}
+
+	totals := pad(options.Catalog.Text("R / H"), textNameWidth)
+	for i, inning := range scorecard.Columns {
+		cell := ""
+		if i == 0 || scorecard.Columns[i-1] != inning {
+			for _, t := range scorecard.Innings {
+				if t.Inning == inning {
+					cell = fmt.Sprintf("%d/%d", t.Runs, t.Hits)
+				}
+			}
+		}
+		totals += pad(cell, textCellWidth)
+	}
+	lines = append(lines, totals)
+
+	return lines
+}
+
+// textLinescore renders the linescore of both teams
+func textLinescore(game *Game, linescore Linescore, options TextOptions) []string {
+	header := pad("", textNameWidth)
+	for inning := 1; inning <= len(linescore.Innings[0]); inning++ {
+		header += padLeft(fmt.Sprintf("%d", inning), 3)
+	}
+	header += options.Catalog.Text("    R  H  E")
+	lines := []string{options.colorize(header, ansiBold)}
+
+	for team, name := range []string{game.Info.Visteam.Name, game.Info.Hometeam.Name} {
+		row := pad(name, textNameWidth)
+		values := linescoreRow(linescore, team)
+		for i, value := range values {
+			if i == len(values)-3 {
+				row += " "
+			}
+			cell := padLeft(value, 3)
+			if value != "0" && value != "X" && i < len(values)-3 {
+				cell = options.colorize(cell, ansiYellow)
+			}
+			row += cell
+		}
+		lines = append(lines, row)
+	}
+
+	return lines
+}
+
+// textBoxScore renders the batting and pitching lines of both teams
+func textBoxScore(game *Game, box BoxScore, options TextOptions) []string {
+	lines := make([]string, 0)
+
+	for team, name := range []string{game.Info.Visteam.Name, game.Info.Hometeam.Name} {
+		lines = append(lines, options.colorize(pad(name, textNameWidth)+options.Catalog.Text("  AB   R   H RBI  BB  SO"), ansiBold))
+		for _, line := range box.Batting[team] {
+			label := fmt.Sprintf("%s %s", line.Name, line.Position)
+			if line.Substitute {
+				label = "  " + label
+			}
+			lines = append(lines, fmt.Sprintf("%s%4d%4d%4d%4d%4d%4d", pad(label, textNameWidth), line.AB, line.R, line.H, line.RBI, line.BB, line.SO))
+		}
+		lines = append(lines, "")
+
+		lines = append(lines, options.colorize(pad(options.Catalog.Text("Pitching"), textNameWidth)+options.Catalog.Text("  IP   H   R  ER  BB  SO"), ansiBold))
+		for _, line := range box.Pitching[team] {
+			lines = append(lines, fmt.Sprintf("%s%4s%4d%4d%4d%4d%4d", pad(line.Name, textNameWidth), line.InningsPitched(), line.H, line.R, line.ER, line.BB, line.SO))
+		}
+		lines = append(lines, "")
+	}
+
+	return lines
+}
+
+// toText Renders a game for the terminal: both scorecard grids, the linescore and the box score
+func (game *Game) toText(options TextOptions) string {
+	box := CreateBoxScore(*game)
+	lines := []string{options.colorize(describeResult(*game), ansiBold)}
+	lines = append(lines, describeGameInfo(game.Info, options.Catalog)...)
+	lines = append(lines, "")
+
+	for team := 0; team < 2; team++ {
+		lines = append(lines, textScorecard(CreateScorecard(*game, team), options)...)
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, textLinescore(game, box.Linescore, options)...)
+	lines = append(lines, "")
+	lines = append(lines, textBoxScore(game, box, options)...)
+
+	return strings.Join(lines, "\n")
+}
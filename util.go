@@ -2,7 +2,6 @@ package main
 
 import (
 	"encoding/csv"
-	"fmt"
 	"strings"
 )
 
@@ -10,6 +9,8 @@ import (
 func GetRecords(source []string) [][]string {
 	csvSource := strings.Join(source, "\r\n")
 	csvReader := csv.NewReader(strings.NewReader(csvSource))
+	// record types have different numbers of fields, so don't hold them to the first one
+	csvReader.FieldsPerRecord = -1
 
 	records, err := csvReader.ReadAll()
 	if err != nil {
@@ -40,18 +41,9 @@ func Contains(values []string, value string) bool {
 	return false
 }
 
-// TranslateModifiers takes in a slice of modifier codes and returns the descriptions as a comma-delimited list ready for printing
+// TranslateModifiers takes in a slice of modifier codes and returns the English descriptions as a comma-delimited list ready for printing
 func TranslateModifiers(modifiers []string) string {
-	if len(modifiers) > 0 {
-		modifierDescriptions := make([]string, 0)
-
-		for _, modifier := range modifiers {
-			modifierDescriptions = append(modifierDescriptions, PlayModifiers[modifier])
-		}
-		return fmt.Sprintf("(%s)", strings.Join(modifierDescriptions, ","))
-	}
-
-	return ""
+	return Catalogs["en"].DescribeModifiers(modifiers)
 }
 
 // AppendUnique will add an item to a slice of strings if it does not exist
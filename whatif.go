@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// WhatIf a real game played again from one of its plays with that play changed
+type WhatIf struct {
+	Game Game `json:"game"`
+	// Changed is the index of the changed play. Followed is how many of the recorded plays after it still fit the game
+	// and were kept, and Simulated is set when what came after them was simulated.
+	Changed   int  `json:"changed"`
+	Followed  int  `json:"followed"`
+	Simulated bool `json:"simulated"`
+}
+
+// whatIfTally what each pitcher has recorded and allowed, and who's due up for each team, as a game is played again
+type whatIfTally struct {
+	outs map[string]int
+	runs map[string]int
+	up   [2]int
+	used map[string]bool
+}
+
+// add counts a play, given the state before it with its half inning entered and the state after it
+func (tally *whatIfTally) add(before GameState, after GameState, batterID string, event Event) {
+	team := before.Half
+	pitcher := before.Defense[1-team][1]
+	tally.used[pitcher] = true
+	tally.outs[pitcher] += after.Outs - before.Outs
+	tally.runs[pitcher] += after.Score[team] - before.Score[team]
+
+	if event.IsPlateAppearance() {
+		for slot := 1; slot <= 9; slot++ {
+			if before.Lineups[team][slot] == batterID {
+				tally.up[team] = slot%9 + 1
+			}
+		}
+	}
+}
+
+// Resimulate plays a game again from a replay position with the play there changed to the given event, which keeps
+// the pitches thrown before the position. With follow set the recorded plays after it are kept for as long as they
+// still fit: the same half inning, the batter who's due up and bases and outs that allow the play. Everything from
+// the first one that doesn't is simulated, as is everything after the change without follow.
+func (model *SimulationModel) Resimulate(game *Game, position ReplayPosition, source string, follow bool, rng *rand.Rand) (WhatIf, error) {
+	replay := CreateReplay(game)
+	if err := replay.Seek(position); err != nil {
+		return WhatIf{}, err
+	}
+	snapshot := replay.Snapshot()
+	if snapshot.Final {
+		return WhatIf{}, fmt.Errorf("the game is over by play %d, there's nothing left to change", position.Play)
+	}
+	event, err := ParseEvent(source)
+	if err != nil {
+		return WhatIf{}, err
+	}
+
+	tally := &whatIfTally{outs: make(map[string]int), runs: make(map[string]int), up: [2]int{1, 1}, used: make(map[string]bool)}
+	for i, play := range game.Plays[:position.Play] {
+		after := replay.states[i]
+		after.Apply(play.Inning, play.Team, play.BatterID, play.Event)
+		tally.add(replay.states[i], after, play.BatterID, play.Event)
+	}
+
+	recorded := game.Plays[position.Play]
+	state := snapshot.GameState
+	if _, _, _, problems := state.Apply(recorded.Inning, recorded.Team, recorded.BatterID, event); len(problems) > 0 {
+		return WhatIf{}, fmt.Errorf("%s doesn't fit play %d: %v", source, position.Play, problems[0])
+	}
+	tally.add(snapshot.GameState, state, recorded.BatterID, event)
+
+	changed := recorded
+	changed.Event, changed.Pitches, changed.Comments = event, snapshot.Pitches, nil
+	if recorded.Pitches != "" {
+		changed.Count = fmt.Sprintf("%d%d", snapshot.Balls, snapshot.Strikes)
+	}
+
+	alternate := *game
+	alternate.Plays = append(append([]Play{}, game.Plays[:position.Play]...), changed)
+	alternate.Comments = append(append([]string{}, game.Comments...), fmt.Sprintf("what if play %d had been %s instead of %s", position.Play, source, recorded.Event.Source))
+	// the decisions, earned runs and records after the last play were those of the real game
+	alternate.EarnedRuns, alternate.TrailingSubstitutions, alternate.TrailingAdjustments = nil, nil, nil
+	alternate.Info.Wp, alternate.Info.Lp, alternate.Info.Save, alternate.Info.Gwrbi = Person{}, Person{}, Person{}, Person{}
+	alternate.Info.Records = make([]InfoRecord, 0)
+	for _, record := range game.Info.Records {
+		switch record.Key {
+		case "wp", "lp", "save", "gwrbi":
+		default:
+			alternate.Info.Records = append(alternate.Info.Records, record)
+		}
+	}
+
+	whatIf := WhatIf{Changed: position.Play}
+	next := position.Play + 1
+	for ; follow && next < len(game.Plays) && !simFinal(state); next++ {
+		play := game.Plays[next]
+		inning, team := state.Inning, state.Half
+		if state.Outs == 3 {
+			inning, team = state.Inning+state.Half, 1-state.Half
+		}
+
+		after := state
+		for _, sub := range play.Substitutions {
+			after.Substitute(sub)
+		}
+		if play.Inning != inning || play.Team != team || play.BatterID != after.Lineups[team][tally.up[team]] {
+			break
+		}
+		after.Enter(inning, team)
+		before := after
+		if _, _, _, problems := after.Apply(inning, team, play.BatterID, play.Event); len(problems) > 0 {
+			break
+		}
+
+		tally.add(before, after, play.BatterID, play.Event)
+		alternate.Plays = append(alternate.Plays, play)
+		state = after
+		whatIf.Followed++
+	}
+
+	records := alternate.eventRecords()
+	// a game that was called ends where the record does rather than being played out
+	called := next == len(game.Plays) && !simFinal(replay.states[len(game.Plays)])
+	if simFinal(state) || called {
+		whatIf.Game = CreateGame(strings.Join(records, "\r\n"))
+		return whatIf, nil
+	}
+
+	sim := &simGame{model: model, rng: rng, state: state, records: records}
+	starters := make(map[string]bool)
+	for _, player := range append(append([]Player{}, game.Lineup.Visitor...), game.Lineup.Home...) {
+		starters[player.ID] = player.FieldingPosition.ID == "1"
+	}
+	for team, code := range []string{game.Info.Visteam.ID, game.Info.Hometeam.ID} {
+		id := state.Defense[team][1]
+		side := &simSide{
+			up:      tally.up[team],
+			pitcher: Player{ID: id, Name: LookupPerson(id).FullName(), FieldingPosition: FieldingPositions["1"]},
+			outs:    tally.outs[id],
+			runs:    tally.runs[id],
+			length:  3,
+		}
+		for slot, player := range state.Lineups[team] {
+			if player == id {
+				side.pitcher.BattingPosition = slot
+			}
+		}
+		if starters[id] {
+			side.length = model.starterLength(id)
+		}
+
+		// pitchers who have already been taken out can't come back
+		bullpen, _ := model.Team(code)
+		for _, reliever := range bullpen.Bullpen {
+			if !tally.used[reliever] && reliever != id {
+				side.bullpen = append(side.bullpen, reliever)
+			}
+		}
+		sim.sides[team] = side
+	}
+
+	whatIf.Game = sim.run()
+	whatIf.Simulated = true
+
+	return whatIf, nil
+}
+
+// WhatIfText the change, how far the record was followed and the two linescores, then the alternate game's play by
+// play from the half inning of the change on
+func WhatIfText(real Game, whatIf WhatIf, options TextOptions) string {
+	recorded := real.Plays[whatIf.Changed]
+	changed := whatIf.Game.Plays[whatIf.Changed]
+	lines := []string{
+		fmt.Sprintf("What if %s's %s in the %s of the %s had been %s?", LookupPerson(recorded.BatterID).FullName(), recorded.Event.Source,
+			[]string{"top", "bottom"}[recorded.Team], ordinal(recorded.Inning), changed.Event.Source),
+	}
+
+	switch {
+	case whatIf.Simulated && whatIf.Followed > 0 && len(whatIf.Game.Plays) > whatIf.Changed+whatIf.Followed+1:
+		first := whatIf.Game.Plays[whatIf.Changed+whatIf.Followed+1]
+		lines = append(lines, fmt.Sprintf("The next %s went as recorded, then the game was simulated from the %s of the %s.",
+			plural(whatIf.Followed, "play", "plays"), []string{"top", "bottom"}[first.Team], ordinal(first.Inning)))
+	case whatIf.Simulated:
+		lines = append(lines, "The rest of the game was simulated.")
+	case whatIf.Followed == 0:
+		lines = append(lines, "That ended the game.")
+	default:
+		lines = append(lines, "The rest of the game went as recorded.")
+	}
+
+	lines = append(lines, "", "As it happened: "+describeResult(real))
+	lines = append(lines, textLinescore(&real, CreateLinescore(real.Plays), options)...)
+	lines = append(lines, "", "What if: "+describeResult(whatIf.Game))
+	lines = append(lines, textLinescore(&whatIf.Game, CreateLinescore(whatIf.Game.Plays), options)...)
+	lines = append(lines, "")
+
+	for _, half := range CreateNarrative(whatIf.Game) {
+		if half.Inning < changed.Inning || (half.Inning == changed.Inning && half.Team < changed.Team) {
+			continue
+		}
+		lines = append(lines, half.Title)
+		lines = append(lines, half.Lines...)
+		lines = append(lines, half.Summary, "")
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// maxRuns caps the runs counted for the rest of a half inning, bigger innings are lumped in with it
+	maxRuns = 10
+	// maxLead caps the score difference tracked by the model
+	maxLead = 30
+	// maxInnings is where extra innings are assumed to end in a coin flip
+	maxInnings = 30
+	// regulationInnings is when a lead can end the game
+	regulationInnings = 9
+)
+
+// transition a base-out state reached from another along with the runs scored getting there
+type transition struct {
+	state BaseOutState
+	runs  int
+}
+
+// WinExpectancy a model of the home team's chance of winning from any inning, score and base-out state.
+// The chance of scoring each number of runs from each base-out state comes from the games it is built with,
+// and the rest of the game is played out half inning by half inning from those odds.
+type WinExpectancy struct {
+	// Distributions is indexed by runners bit mask, outs and runs scored through the end of the half inning
+	Distributions [8][3][maxRuns + 1]float64 `json:"distributions"`
+
+	// halfStart holds the home team's chance of winning at the start of each half inning by score difference
+	halfStart [maxInnings + 2][2][2*maxLead + 1]float64
+	// transitions holds how often each state leads to another
+	transitions [8][3]map[transition]float64
+	// averageSwing is the mean expected change in win probability of a play, used to scale leverage
+	averageSwing float64
+}
+
+// PlayerWinValue the total win probability added by the plays a player was involved in
+type PlayerWinValue struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Plays int     `json:"plays"`
+	WPA   float64 `json:"wpa"`
+}
+
+// BuildWinExpectancy Given a collection of games, return the win expectancy model they produce
+func BuildWinExpectancy(games []Game) WinExpectancy {
+	we := WinExpectancy{}
+	counts := [8][3][maxRuns + 1]int{}
+	totals := [8][3]int{}
+	transitionCounts := [8][3]map[transition]int{}
+
+	for runners := 0; runners < 8; runners++ {
+		for outs := 0; outs < 3; outs++ {
+			transitionCounts[runners][outs] = make(map[transition]int)
+		}
+	}
+
+	for _, game := range games {
+		for _, half := range halfInnings(game.Plays) {
+			if half[len(half)-1].After.Outs < 3 {
+				continue
+			}
+
+			remaining := 0
+			for _, play := range half {
+				remaining += play.Runs
+			}
+
+			for _, play := range half {
+				if play.Event.Type != EventNone {
+					before := play.Before.BaseOut()
+					after := play.After.BaseOut()
+					if after.Outs >= 3 {
+						after = BaseOutState{Outs: 3}
+					}
+
+					counts[before.Runners][before.Outs][int(math.Min(float64(remaining), maxRuns))]++
+					totals[before.Runners][before.Outs]++
+					transitionCounts[before.Runners][before.Outs][transition{state: after, runs: play.Runs}]++
+				}
+				remaining -= play.Runs
+			}
+		}
+	}
+
+	for runners := 0; runners < 8; runners++ {
+		for outs := 0; outs < 3; outs++ {
+			we.transitions[runners][outs] = make(map[transition]float64)
+			total := totals[runners][outs]
+
+			// a state never seen falls back on the odds from the start of an inning
+			if total == 0 {
+				we.Distributions[runners][outs] = we.Distributions[0][0]
+				continue
+			}
+
+			for r := 0; r <= maxRuns; r++ {
+				we.Distributions[runners][outs][r] = float64(counts[runners][outs][r]) / float64(total)
+			}
+			for t, count := range transitionCounts[runners][outs] {
+				we.transitions[runners][outs][t] = float64(count) / float64(total)
+			}
+		}
+	}
+
+	we.fillHalfStarts()
+
+	swings := 0.0
+	plays := 0
+	for _, game := range games {
+		for _, play := range game.Plays {
+			if play.Event.Type != EventNone {
+				swings += we.swing(play.Inning, play.Team, play.Before.BaseOut(), scoreDifference(play.Before))
+				plays++
+			}
+		}
+	}
+	if plays > 0 {
+		we.averageSwing = swings / float64(plays)
+	}
+
+	return we
+}
+
+// scoreDifference the home team's lead in a situation
+func scoreDifference(situation Situation) int {
+	return situation.Score[1] - situation.Score[0]
+}
+
+// clampLead keeps a score difference within the range tracked by the model
+func clampLead(diff int) int {
+	if diff > maxLead {
+		return maxLead
+	}
+	if diff < -maxLead {
+		return -maxLead
+	}
+
+	return diff
+}
+
+// decided the home team's chance of winning once the game is over
+func decided(diff int) float64 {
+	switch {
+	case diff > 0:
+		return 1
+	case diff < 0:
+		return 0
+	}
+
+	return 0.5
+}
+
+// fillHalfStarts works backwards from the last inning to find the chance of winning at the start of every half inning
+func (we *WinExpectancy) fillHalfStarts() {
+	inningStart := we.Distributions[0][0]
+
+	for diff := -maxLead; diff <= maxLead; diff++ {
+		we.halfStart[maxInnings+1][0][diff+maxLead] = decided(diff)
+	}
+
+	for inning := maxInnings; inning >= 1; inning-- {
+		for diff := -maxLead; diff <= maxLead; diff++ {
+			if inning >= regulationInnings && diff > 0 {
+				we.halfStart[inning][1][diff+maxLead] = 1
+				continue
+			}
+
+			wp := 0.0
+			for r, p := range inningStart {
+				wp += p * we.endOfHalf(inning, 1, diff+r)
+			}
+			we.halfStart[inning][1][diff+maxLead] = wp
+		}
+
+		for diff := -maxLead; diff <= maxLead; diff++ {
+			wp := 0.0
+			for r, p := range inningStart {
+				wp += p * we.halfStart[inning][1][clampLead(diff-r)+maxLead]
+			}
+			we.halfStart[inning][0][diff+maxLead] = wp
+		}
+	}
+}
+
+// endOfHalf the home team's chance of winning once the given half inning is over
+func (we *WinExpectancy) endOfHalf(inning int, half int, diff int) float64 {
+	diff = clampLead(diff)
+	if inning > maxInnings {
+		inning = maxInnings
+	}
+
+	if half == 0 {
+		return we.halfStart[inning][1][diff+maxLead]
+	}
+
+	if inning >= regulationInnings && diff != 0 {
+		return decided(diff)
+	}
+
+	return we.halfStart[inning+1][0][diff+maxLead]
+}
+
+// Value the home team's chance of winning in the given inning, half, base-out state and home lead
+func (we *WinExpectancy) Value(inning int, half int, state BaseOutState, diff int) float64 {
+	if inning > maxInnings {
+		inning = maxInnings
+	}
+
+	// the home team wins as soon as it leads in the bottom of the last inning
+	if half == 1 && inning >= regulationInnings && diff > 0 {
+		return 1
+	}
+
+	if state.Outs >= 3 {
+		return we.endOfHalf(inning, half, diff)
+	}
+
+	wp := 0.0
+	for r, p := range we.Distributions[state.Runners][state.Outs] {
+		if half == 0 {
+			wp += p * we.endOfHalf(inning, half, diff-r)
+		} else {
+			wp += p * we.endOfHalf(inning, half, diff+r)
+		}
+	}
+
+	return wp
+}
+
+// swing the expected absolute change in win probability of the next play from a situation
+func (we *WinExpectancy) swing(inning int, half int, state BaseOutState, diff int) float64 {
+	if state.Outs >= 3 {
+		return 0
+	}
+
+	before := we.Value(inning, half, state, diff)
+	swing := 0.0
+
+	for t, p := range we.transitions[state.Runners][state.Outs] {
+		after := diff + t.runs
+		if half == 0 {
+			after = diff - t.runs
+		}
+		swing += p * math.Abs(we.Value(inning, half, t.state, after)-before)
+	}
+
+	return swing
+}
+
+// LeverageIndex how much the next play from a situation can swing the game compared to an average play
+func (we *WinExpectancy) LeverageIndex(inning int, half int, state BaseOutState, diff int) float64 {
+	if we.averageSwing == 0 {
+		return 0
+	}
+
+	return we.swing(inning, half, state, diff) / we.averageSwing
+}
+
+// ApplyWinProbabilities annotates every play of the games with the home team's chance of winning before and after it,
+// the win probability added for the batting team and the leverage of the situation
+func (we *WinExpectancy) ApplyWinProbabilities(games []Game) {
+	for g := range games {
+		plays := games[g].Plays
+
+		for p := range plays {
+			play := &plays[p]
+			before := play.Before.BaseOut()
+			play.WinProbabilityBefore = we.Value(play.Inning, play.Team, before, scoreDifference(play.Before))
+			play.WinProbabilityAfter = we.Value(play.Inning, play.Team, play.After.BaseOut(), scoreDifference(play.After))
+			play.LeverageIndex = we.LeverageIndex(play.Inning, play.Team, before, scoreDifference(play.Before))
+
+			// the last play settles the game no matter what the model expects
+			if p == len(plays)-1 {
+				play.WinProbabilityAfter = decided(scoreDifference(play.After))
+			}
+
+			play.WPA = play.WinProbabilityAfter - play.WinProbabilityBefore
+			if play.Team == 0 {
+				play.WPA = -play.WPA
+			}
+		}
+	}
+}
+
+// BiggestSwings the plays of the game that moved the win probability the most, biggest first
+func (game *Game) BiggestSwings(count int) []Play {
+	plays := make([]Play, len(game.Plays))
+	copy(plays, game.Plays)
+
+	sort.SliceStable(plays, func(i, j int) bool {
+		return math.Abs(plays[i].WPA) > math.Abs(plays[j].WPA)
+	})
+
+	if count < len(plays) {
+		plays = plays[:count]
+	}
+
+	return plays
+}
+
+// AggregateWPA totals the win probability added of annotated plays by batter and by pitcher, sorted best first.
+// Both are credited with every play while they are in the matchup, pitchers from the defense's side.
+func AggregateWPA(games []Game) ([]PlayerWinValue, []PlayerWinValue) {
+	batters := make(map[string]*PlayerWinValue)
+	pitchers := make(map[string]*PlayerWinValue)
+	credit := func(values map[string]*PlayerWinValue, id string, value float64) {
+		if _, ok := values[id]; !ok {
+			values[id] = &PlayerWinValue{ID: id, Name: LookupPerson(id).FullName()}
+		}
+		values[id].Plays++
+		values[id].WPA += value
+	}
+
+	for _, game := range games {
+		for _, play := range game.Plays {
+			if play.Event.Type == EventNone {
+				continue
+			}
+			credit(batters, play.BatterID, play.WPA)
+			credit(pitchers, play.PitcherID, -play.WPA)
+		}
+	}
+
+	return sortWinValues(batters), sortWinValues(pitchers)
+}
+
+// sortWinValues flattens a map of win values into a slice ordered from highest to lowest
+func sortWinValues(values map[string]*PlayerWinValue) []PlayerWinValue {
+	list := make([]PlayerWinValue, 0)
+	for _, v := range values {
+		list = append(list, *v)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].WPA == list[j].WPA {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].WPA > list[j].WPA
+	})
+
+	return list
+}